@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/config"
+	"go.yaml.in/yaml/v3"
+)
+
+const redacted = "<redacted>"
+
+func runConfig(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: crontask config <init|show|validate> [flags]")
+		return 2
+	}
+
+	switch args[0] {
+	case "init":
+		return configInit(args[1:])
+	case "show":
+		return configShow(args[1:])
+	case "validate":
+		return configValidate(args[1:])
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown config command %q\n", args[0])
+	return 2
+}
+
+// configInit writes the default configuration file
+func configInit(args []string) int {
+	fs := newFlagSet("config init", "")
+	path := fs.String("path", "", "File to create, the system config directory if empty")
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	target := *path
+	if target == "" {
+		dir, err := config.GetSystemConfigDir()
+		if err != nil {
+			return fail("failed to locate config directory: %s", err)
+		}
+		target = filepath.Join(dir, "crontaskd.yaml")
+	}
+
+	if err := config.CreateDefaultConfigAt(target); err != nil {
+		return fail("failed to create %s: %s", target, err)
+	}
+
+	fmt.Printf("Created %s\n", target)
+	return 0
+}
+
+// configShow prints the effective configuration, defaults, file and
+// environment merged, with secrets redacted
+func configShow(args []string) int {
+	fs := newFlagSet("config show", "")
+	configFile := configFlag(fs)
+	asJSON := jsonFlag(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadFrom(*configFile)
+	if err != nil {
+		return fail("failed to load config: %s", err)
+	}
+
+	for i := range cfg.API.Tokens {
+		cfg.API.Tokens[i].Token = redacted
+	}
+
+	values := configValues(reflect.ValueOf(*cfg))
+	if *asJSON {
+		return printJSON(values)
+	}
+
+	if source := config.GetConfigFileLocation(); source != "" {
+		fmt.Printf("# Loaded from %s\n", source)
+	} else {
+		fmt.Println("# No config file found, defaults and environment only")
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(values); err != nil {
+		return fail("failed to encode config: %s", err)
+	}
+	return 0
+}
+
+// configValidate loads the configuration and reports every problem found
+func configValidate(args []string) int {
+	fs := newFlagSet("config validate", "")
+	configFile := configFlag(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadFrom(*configFile)
+	if err != nil {
+		return fail("failed to load config: %s", err)
+	}
+
+	source := config.GetConfigFileLocation()
+	if source == "" {
+		source = "defaults"
+	}
+
+	errs := config.Validate(cfg)
+	if len(errs) == 0 {
+		fmt.Printf("%s: configuration is valid\n", source)
+		return 0
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", source, err)
+	}
+	return 1
+}
+
+// configValues converts the config into maps keyed like the config file,
+// so it prints with the same names users write
+func configValues(value reflect.Value) interface{} {
+	if duration, ok := value.Interface().(time.Duration); ok {
+		return duration.String()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		values := make(map[string]interface{})
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "" || name == "-" {
+				name = strings.ToLower(field.Name)
+			}
+			values[name] = configValues(value.Field(i))
+		}
+		return values
+
+	case reflect.Slice:
+		values := make([]interface{}, value.Len())
+		for i := range values {
+			values[i] = configValues(value.Index(i))
+		}
+		return values
+
+	case reflect.Map:
+		values := make(map[string]interface{})
+		for _, key := range value.MapKeys() {
+			values[fmt.Sprint(key.Interface())] = configValues(value.MapIndex(key))
+		}
+		return values
+	}
+
+	return value.Interface()
+}
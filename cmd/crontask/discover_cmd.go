@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/config"
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+	"github.com/amir-mohammad-HP/crontask/pkg/logger"
+)
+
+// discoveredJob is a job that would be registered for a container
+type discoveredJob struct {
+	Name        string `json:"name"`
+	Trigger     string `json:"trigger"`
+	Expression  string `json:"expression,omitempty"`
+	Description string `json:"description"`
+	Task        string `json:"task"`
+}
+
+// discoveredContainer is a labelled container with its jobs and problems
+type discoveredContainer struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	State    string          `json:"state"`
+	Image    string          `json:"image"`
+	Jobs     []discoveredJob `json:"jobs"`
+	Errors   []string        `json:"errors,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+// runDiscover lists the labelled containers and lints their labels the way
+// the daemon would parse them. It fails on errors, and on warnings with
+// --strict, so it can gate deployments in CI.
+func runDiscover(args []string) int {
+	fs := newFlagSet("discover", "")
+	configFile := configFlag(fs)
+	all := fs.Bool("all", false, "Include stopped containers")
+	strict := fs.Bool("strict", false, "Fail on warnings too")
+	asJSON := jsonFlag(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadFrom(*configFile)
+	if err != nil {
+		return fail("failed to load config: %s", err)
+	}
+
+	// Connection errors are returned, the monitor's own logging is noise here
+	logConfig := cfg.Logger
+	logConfig.Level = "fatal"
+	logConfig.Output = "stderr"
+	logConfig.Async = false
+	monitor, err := docker.NewMonitor(&cfg.Docker, logger.NewWithConfig(&logConfig))
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	containers, err := monitor.GetContainersWithCronJobs(*all)
+	if err != nil {
+		return fail("failed to list containers: %s", err)
+	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+
+	results := lintContainers(monitor, containers)
+
+	errorCount, warningCount, jobCount := 0, 0, 0
+	for _, result := range results {
+		errorCount += len(result.Errors)
+		warningCount += len(result.Warnings)
+		jobCount += len(result.Jobs)
+	}
+
+	if *asJSON {
+		if code := printJSON(results); code != 0 {
+			return code
+		}
+	} else {
+		printDiscovery(results)
+		fmt.Printf("%d containers, %d jobs, %d errors, %d warnings\n",
+			len(results), jobCount, errorCount, warningCount)
+	}
+
+	if errorCount > 0 || (*strict && warningCount > 0) {
+		return 1
+	}
+	return 0
+}
+
+// lintContainers parses every container's labels and checks the jobs
+// against each other like the registry does
+func lintContainers(monitor *docker.DockerMonitor, containers []docker.ContainerInfo) []*discoveredContainer {
+	registry := job.NewJobRegistry(monitor)
+	results := make([]*discoveredContainer, 0, len(containers))
+	var registered [][]*job.DockerJob
+
+	for i := range containers {
+		container := &containers[i]
+		result := &discoveredContainer{
+			ID:    container.ID[:12],
+			Name:  container.Name,
+			State: container.State,
+			Image: container.Image,
+			Jobs:  []discoveredJob{},
+		}
+
+		cronJobs, errs := monitor.ParseCronJobs(container)
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+
+		var jobs []*job.DockerJob
+		seen := make(map[string]string)
+		for _, cronJob := range cronJobs {
+			// Names differing only in case register separately but are
+			// easily mixed up in dependencies
+			folded := strings.ToLower(cronJob.Name)
+			if other, ok := seen[folded]; ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("job names %q and %q differ only in case", other, cronJob.Name))
+			}
+			seen[folded] = cronJob.Name
+
+			dockerJob := job.NewDockerJob(cronJob, monitor)
+			if !registry.AddJob(dockerJob) {
+				result.Errors = append(result.Errors, fmt.Sprintf("duplicate job name %q", cronJob.Name))
+				continue
+			}
+			jobs = append(jobs, dockerJob)
+
+			result.Jobs = append(result.Jobs, discoveredJob{
+				Name:        cronJob.Name,
+				Trigger:     cronJob.Trigger,
+				Expression:  cronJob.CronExpr,
+				Description: dockerJob.Describe(),
+				Task:        cronJob.Task,
+			})
+		}
+
+		missing, err := checkExecutables(monitor, container, jobs)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("could not check for executables: %s", err))
+		}
+		result.Errors = append(result.Errors, missing...)
+
+		sort.Slice(result.Jobs, func(i, j int) bool { return result.Jobs[i].Name < result.Jobs[j].Name })
+		sort.Strings(result.Errors)
+
+		results = append(results, result)
+		registered = append(registered, jobs)
+	}
+
+	// Dependencies are checked once every job is known
+	for i, jobs := range registered {
+		for _, dockerJob := range jobs {
+			missing, err := registry.CheckDependencies(dockerJob)
+			if err != nil {
+				results[i].Errors = append(results[i].Errors, fmt.Sprintf("job %q: %s", dockerJob.JobName(), err))
+			}
+			for _, reason := range missing {
+				results[i].Warnings = append(results[i].Warnings, fmt.Sprintf("job %q: %s", dockerJob.JobName(), reason))
+			}
+		}
+	}
+
+	return results
+}
+
+// checkExecutables reports jobs whose shell, or exec form executable, is
+// missing from the container, as they would fail on every run
+func checkExecutables(monitor *docker.DockerMonitor, container *docker.ContainerInfo, jobs []*job.DockerJob) ([]string, error) {
+	var missing []string
+	found := make(map[string]bool)
+
+	for _, dockerJob := range jobs {
+		args, err := docker.Command(dockerJob.Task(), dockerJob.ExecOptions().Shell)
+		if err != nil {
+			continue
+		}
+
+		executable := args[0]
+		ok, checked := found[executable]
+		if !checked {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			ok, err = monitor.HasExecutable(ctx, container.ID, executable)
+			cancel()
+			if err != nil {
+				return missing, err
+			}
+			found[executable] = ok
+		}
+
+		if !ok {
+			missing = append(missing, fmt.Sprintf("job %q: %s not found in the container", dockerJob.JobName(), executable))
+		}
+	}
+
+	return missing, nil
+}
+
+func printDiscovery(results []*discoveredContainer) {
+	for _, result := range results {
+		fmt.Printf("%s (%s, %s, %s)\n", result.Name, result.ID, result.State, result.Image)
+
+		if len(result.Jobs) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "  NAME\tTRIGGER\tSCHEDULE\tTASK")
+			for _, job := range result.Jobs {
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", job.Name, job.Trigger, job.Description, job.Task)
+			}
+			w.Flush()
+		}
+
+		for _, err := range result.Errors {
+			fmt.Printf("  error: %s\n", err)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Printf("  warning: %s\n", warning)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/config"
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+	"github.com/amir-mohammad-HP/crontask/pkg/logger"
+)
+
+// runExec runs a job's task, or an ad-hoc one, in a container through the
+// same exec path as the scheduler and streams its output
+func runExec(args []string) int {
+	fs := newFlagSet("exec", "<container>")
+	configFile := configFlag(fs)
+	jobName := fs.String("job", "", "Run the task of this job from the container's labels")
+	task := fs.String("task", "", "Run this task instead of a job's")
+	timeout := fs.Duration("timeout", 0, "Stop waiting for the task after this long, 0 waits forever")
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || (*jobName == "") == (*task == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --job and --task is required")
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.LoadFrom(*configFile)
+	if err != nil {
+		return fail("failed to load config: %s", err)
+	}
+
+	// Connection errors are returned, the monitor's own logging is noise here
+	logConfig := cfg.Logger
+	logConfig.Level = "fatal"
+	logConfig.Output = "stderr"
+	logConfig.Async = false
+	monitor, err := docker.NewMonitor(&cfg.Docker, logger.NewWithConfig(&logConfig))
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	container, err := monitor.InspectContainer(fs.Arg(0))
+	if err != nil {
+		return fail("failed to find container %s: %s", fs.Arg(0), err)
+	}
+
+	dockerJob, err := execJob(monitor, container, *jobName, *task)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	runID := job.NewRunID()
+	fmt.Fprintf(os.Stderr, "Running %s in %s (%s), run %s\n", dockerJob.JobName(), container.Name, container.ID[:12], runID)
+
+	started := time.Now()
+	run := job.RunContext{ID: runID, Scheduled: started, Attempt: 1}
+	result, err := dockerJob.ExecuteStream(ctx, run, nil, os.Stdout, os.Stderr)
+	duration := time.Since(started).Truncate(time.Millisecond)
+
+	if result == nil || result.ExitCode < 0 {
+		return fail("failed after %s: %s", duration, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exit code %d after %s\n", result.ExitCode, duration)
+	if result.ExitCode > 255 {
+		return 1
+	}
+	return result.ExitCode
+}
+
+// execJob builds the job to run, from the container's labels or an ad-hoc
+// task, so it runs exactly as the scheduler would run it
+func execJob(monitor *docker.DockerMonitor, container *docker.ContainerInfo, jobName string, task string) (*job.DockerJob, error) {
+	if task != "" {
+		return job.NewDockerJob(types.CronJob{
+			ContainerID:   container.ID,
+			ContainerName: container.Name,
+			Name:          "adhoc",
+			Trigger:       types.TriggerChain,
+			Task:          task,
+			Exec:          monitor.DefaultExecOptions(),
+		}, monitor), nil
+	}
+
+	cronJobs, errs := monitor.ParseCronJobs(container)
+	var names []string
+	for _, cronJob := range cronJobs {
+		if cronJob.Name == jobName {
+			return job.NewDockerJob(cronJob, monitor), nil
+		}
+		names = append(names, cronJob.Name)
+	}
+
+	// The job may be one of the labels that failed to parse
+	for _, err := range errs {
+		if strings.Contains(err.Error(), fmt.Sprintf("job %q", jobName)) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("container %s has no job %q, jobs: %s",
+		container.Name, jobName, strings.Join(names, ", "))
+}
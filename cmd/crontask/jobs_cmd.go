@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/api"
+	"github.com/amir-mohammad-HP/crontask/internal/config"
+)
+
+func runJobs(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: crontask jobs <list|trigger|history> [flags]")
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		return jobsList(args[1:])
+	case "trigger":
+		return jobsTrigger(args[1:])
+	case "history":
+		return jobsHistory(args[1:])
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown jobs command %q\n", args[0])
+	return 2
+}
+
+// clientFlags are shared by the commands talking to a running daemon
+type clientFlags struct {
+	config *string
+	addr   *string
+	token  *string
+}
+
+func addClientFlags(fs *flag.FlagSet) clientFlags {
+	return clientFlags{
+		config: configFlag(fs),
+		addr:   fs.String("addr", "", "API address, api.listen from the config or the local control socket if empty"),
+		token:  fs.String("token", os.Getenv("CRONTASK_API_TOKEN"), "API token, defaults to $CRONTASK_API_TOKEN"),
+	}
+}
+
+// client builds an API client from the daemon's own config file
+func (f clientFlags) client() (*api.Client, error) {
+	cfg, err := config.LoadFrom(*f.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiConfig := cfg.API
+	if *f.addr != "" {
+		apiConfig.Listen = *f.addr
+	}
+
+	return api.NewClient(&apiConfig, *f.token)
+}
+
+func jobsList(args []string) int {
+	fs := newFlagSet("jobs list", "")
+	flags := addClientFlags(fs)
+	asJSON := jsonFlag(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	client, err := flags.client()
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	jobs, err := client.ListJobs()
+	if err != nil {
+		return fail("failed to list jobs: %s", err)
+	}
+
+	if *asJSON {
+		return printJSON(jobs)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTRIGGER\tEXPRESSION\tSCHEDULE\tNEXT RUN\tLAST RUN\tPAUSED")
+	for _, job := range jobs {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			job["key"], job["trigger"], orDash(job["expression"]), orDash(job["description"]),
+			orDash(job["next_run"]), orDash(job["last_run"]), job["paused"])
+	}
+	w.Flush()
+	return 0
+}
+
+func jobsTrigger(args []string) int {
+	fs := newFlagSet("jobs trigger", "<job>")
+	flags := addClientFlags(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	client, err := flags.client()
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	runID, err := client.TriggerJob(fs.Arg(0))
+	if err != nil {
+		return fail("failed to trigger %s: %s", fs.Arg(0), err)
+	}
+
+	fmt.Printf("Triggered %s, run %s\n", fs.Arg(0), runID)
+	return 0
+}
+
+func jobsHistory(args []string) int {
+	fs := newFlagSet("jobs history", "<job>")
+	flags := addClientFlags(fs)
+	limit := fs.Int("limit", 20, "Most recent runs shown")
+	asJSON := jsonFlag(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	client, err := flags.client()
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	records, err := client.JobHistory(fs.Arg(0), *limit)
+	if err != nil {
+		return fail("failed to read history of %s: %s", fs.Arg(0), err)
+	}
+
+	if *asJSON {
+		return printJSON(records)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tSTARTED\tDURATION\tOUTCOME\tEXIT\tATTEMPT\tREASON")
+	for _, record := range records {
+		duration := "-"
+		if !record.FinishedAt.IsZero() {
+			duration = record.FinishedAt.Sub(record.StartedAt).Truncate(time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			record.RunID, record.StartedAt.Format(time.RFC3339), duration,
+			record.Outcome, record.ExitCode, record.Attempt, orDash(record.Reason))
+	}
+	w.Flush()
+	return 0
+}
+
+// orDash prints empty table cells as "-"
+func orDash(value interface{}) string {
+	if s := fmt.Sprint(value); s != "" && value != nil {
+		return s
+	}
+	return "-"
+}
@@ -1,47 +1,74 @@
 package main
 
 import (
-	"log"
+	"fmt"
 	"os"
-
-	"github.com/amir-mohammad-HP/crontask/internal/app"
-	"github.com/amir-mohammad-HP/crontask/internal/config"
-	"github.com/amir-mohammad-HP/crontask/pkg/logger"
 )
 
 // main is the entry point of the crontask application.
-// It performs the following sequence:
-//  1. Loads application configuration
-//  2. Initializes the structured logger
-//  3. Creates and runs the main application instance
+// Without a subcommand it runs the daemon, otherwise it dispatches to one
+// of the commands below.
 //
 // Exit codes:
 //   - 0: Successful execution
-//   - 1: Configuration loading failed or application runtime error
+//   - 1: Configuration loading failed, application runtime error or a failed command
+//   - 2: Invalid usage
 func main() {
-	// Load configuration from environment variables and/or config files
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+	os.Exit(execute(os.Args[1:]))
+}
+
+// command is a subcommand taking the remaining arguments
+type command struct {
+	run   func(args []string) int
+	usage string
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"run":      {runDaemon, "Run the scheduler daemon (default)"},
+		"config":   {runConfig, "Create, show or validate the configuration"},
+		"discover": {runDiscover, "List labelled containers and lint their job labels"},
+		"exec":     {runExec, "Run a job's task in a container and stream its output"},
+		"jobs":     {runJobs, "List, trigger and inspect jobs of a running daemon"},
+		"schedule": {runSchedule, "Preview when a cron expression fires"},
+		"version":  {runVersion, "Print version information"},
 	}
+}
 
-	// Log the config file location if one was found
-	if configFile := config.GetConfigFileLocation(); configFile != "" {
-		log.Printf("Loaded configuration from: %s", configFile)
+func execute(args []string) int {
+	if len(args) == 0 {
+		return runDaemon(nil)
 	}
 
-	// Setup logger with full configuration
-	logger := logger.NewWithConfig(&cfg.Logger)
+	switch args[0] {
+	case "help", "-h", "--help":
+		printUsage()
+		return 0
+	}
 
-	logger.Info("environment: %s", cfg.Environment)
-	logger.Info("loglevel: %s", cfg.LogLevel)
+	cmd, ok := commands[args[0]]
+	if !ok {
+		// Flags without a subcommand still start the daemon
+		if len(args[0]) > 0 && args[0][0] == '-' {
+			return runDaemon(args)
+		}
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printUsage()
+		return 2
+	}
 
-	// Create application instance with dependencies
-	app := app.New(cfg, logger)
+	return cmd.run(args[1:])
+}
 
-	// Run the main application loop
-	if err := app.Run(); err != nil {
-		logger.Error("Application failed: %s", err)
-		os.Exit(1)
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: crontask <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range sortedKeys(commands) {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
 	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'crontask <command> -h' for the flags of a command.")
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/amir-mohammad-HP/crontask/internal/app"
+	"github.com/amir-mohammad-HP/crontask/internal/config"
+	"github.com/amir-mohammad-HP/crontask/pkg/logger"
+)
+
+// runDaemon performs the following sequence:
+//  1. Loads and validates application configuration
+//  2. Initializes the structured logger
+//  3. Creates and runs the main application instance
+func runDaemon(args []string) int {
+	fs := newFlagSet("run", "")
+	configFile := configFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "Schedule and record runs without executing anything, like worker.dry_run")
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	// Load configuration from environment variables and/or config files
+	cfg, err := config.LoadFrom(*configFile)
+	if err != nil {
+		log.Printf("Failed to load config: %v", err)
+		return 1
+	}
+
+	// Log the config file location if one was found
+	if configFile := config.GetConfigFileLocation(); configFile != "" {
+		log.Printf("Loaded configuration from: %s", configFile)
+	}
+
+	if *dryRun {
+		cfg.Worker.DryRun = true
+	}
+
+	if errs := config.Validate(cfg); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "invalid configuration: %s\n", err)
+		}
+		return 1
+	}
+
+	// Setup logger with full configuration
+	logger := logger.NewWithConfig(&cfg.Logger)
+
+	logger.Info("environment: %s", cfg.Environment)
+	logger.Info("loglevel: %s", cfg.LogLevel)
+
+	// Create application instance with dependencies
+	app := app.New(cfg, logger)
+
+	// Run the main application loop
+	if err := app.Run(); err != nil {
+		logger.Error("Application failed: %s", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/config"
+	"github.com/amir-mohammad-HP/crontask/internal/schedule"
+)
+
+func runSchedule(args []string) int {
+	if len(args) == 0 || args[0] != "preview" {
+		fmt.Fprintln(os.Stderr, "Usage: crontask schedule preview [flags] '<expression>'")
+		return 2
+	}
+	return schedulePreview(args[1:])
+}
+
+// schedulePreview parses an expression exactly like the worker does and
+// prints its next fire times
+func schedulePreview(args []string) int {
+	fs := newFlagSet("schedule preview", "'<expression>'")
+	configFile := configFlag(fs)
+	tz := fs.String("tz", "", "Time zone of the job, scheduler.timezone from the config if empty")
+	count := fs.Int("count", 5, "Number of fire times shown")
+	key := fs.String("job", "", "Job key <container>/<job> used to resolve H tokens")
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || *count < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.LoadFrom(*configFile)
+	if err != nil {
+		return fail("failed to load config: %s", err)
+	}
+
+	location, err := schedule.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	// A job zone is applied like the timezone label, as a CRON_TZ= prefix
+	expr := fs.Arg(0)
+	if *tz != "" {
+		if _, err := schedule.LoadLocation(*tz); err != nil {
+			return fail("%s", err)
+		}
+		expr = schedule.WithTimezone(expr, *tz)
+	}
+
+	resolved, err := schedule.ResolveHash(expr, *key)
+	if err != nil {
+		return fail("invalid cron expression: %s", err)
+	}
+
+	sched, err := schedule.Parse(resolved)
+	if err != nil {
+		return fail("invalid cron expression: %s", err)
+	}
+
+	description, err := schedule.Describe(expr, *key)
+	if err != nil {
+		return fail("invalid cron expression: %s", err)
+	}
+
+	if zone := schedule.Timezone(expr); zone != "" {
+		location, _ = schedule.LoadLocation(zone)
+	}
+
+	fmt.Printf("Expression:  %s\n", fs.Arg(0))
+	if resolved != expr {
+		fmt.Printf("Resolved:    %s\n", resolved)
+	}
+	fmt.Printf("Description: %s\n", description)
+	fmt.Printf("Time zone:   %s\n\n", location)
+
+	fmt.Printf("Next %d runs:\n", *count)
+	next := time.Now().In(location)
+	for i := 0; i < *count; i++ {
+		next = sched.Next(next)
+		if next.IsZero() {
+			fmt.Println("  (no further runs)")
+			break
+		}
+		fmt.Printf("  %s\n", next.In(location).Format("Mon 2006-01-02 15:04:05 MST"))
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Set at build time with -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = "dev"
+	commit  = "unknown"
+	date    = "unknown"
+)
+
+func runVersion(args []string) int {
+	fs := newFlagSet("version", "")
+	asJSON := jsonFlag(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return 2
+	}
+
+	if *asJSON {
+		return printJSON(map[string]string{
+			"version": version,
+			"commit":  commit,
+			"date":    date,
+			"go":      runtime.Version(),
+		})
+	}
+
+	fmt.Printf("crontask %s (commit %s, built %s, %s %s/%s)\n",
+		version, commit, date, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return 0
+}
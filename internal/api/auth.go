@@ -0,0 +1,153 @@
+// internal/api/auth.go
+package api
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+// identity is an authenticated caller
+type identity struct {
+	name  string
+	scope string
+}
+
+func (id *identity) allows(scope string) bool {
+	return id.scope == types.ScopeOperator || id.scope == scope
+}
+
+// authEnabled reports whether callers must identify themselves
+func (s *Server) authEnabled() bool {
+	return len(s.config.Tokens) > 0 || s.config.TLS.ClientCAFile != ""
+}
+
+// authenticate identifies the caller by bearer token, then by verified
+// client certificate. Without configured credentials callers are anonymous:
+// operators on a Unix socket in a directory private to the daemon's user,
+// whose permissions guard it, read-only anywhere else.
+func (s *Server) authenticate(r *http.Request) (*identity, error) {
+	if !s.authEnabled() {
+		if s.privateSocket {
+			return &identity{name: "anonymous", scope: types.ScopeOperator}, nil
+		}
+		return &identity{name: "anonymous", scope: types.ScopeRead}, nil
+	}
+
+	if header := r.Header.Get("Authorization"); header != "" {
+		secret, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return nil, fmt.Errorf("unsupported authorization scheme")
+		}
+
+		for _, token := range s.config.Tokens {
+			if subtle.ConstantTimeCompare([]byte(secret), []byte(token.Token)) == 1 {
+				return &identity{name: "token:" + token.Name, scope: token.Scope}, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		cert := r.TLS.VerifiedChains[0][0]
+		return &identity{name: "cert:" + cert.Subject.CommonName, scope: s.config.TLS.ClientScope}, nil
+	}
+
+	return nil, fmt.Errorf("missing credentials")
+}
+
+// require wraps a handler with authentication, a scope check and, for
+// mutating requests, an audit entry
+func (s *Server) require(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		caller := "anonymous"
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			defer func() { s.audit(r, caller, recorder.status) }()
+		}
+
+		id, err := s.authenticate(r)
+		if err != nil {
+			recorder.Header().Set("WWW-Authenticate", `Bearer realm="crontask"`)
+			writeJSON(recorder, http.StatusUnauthorized, errorBody(err.Error()))
+			return
+		}
+		caller = id.name
+
+		if !id.allows(scope) {
+			writeJSON(recorder, http.StatusForbidden, errorBody(fmt.Sprintf("%s scope required", scope)))
+			return
+		}
+
+		handler(recorder, r)
+	}
+}
+
+// audit records who attempted a mutating request and how it ended
+func (s *Server) audit(r *http.Request, caller string, status int) {
+	s.logger.Info("API audit | %s: %s, %s: %s, %s: %s, %s: %s, %s: %d",
+		"caller", caller,
+		"remote", r.RemoteAddr,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", status)
+}
+
+// tlsConfig builds the server TLS settings, nil when TLS is off
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	cfg := s.config.TLS
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, fmt.Errorf("api client_ca_file needs cert_file and key_file")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load api certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read api client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+
+		// Token holders may still connect without a certificate
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		if len(s.config.Tokens) > 0 {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// statusRecorder remembers the status written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tokens := []types.APIToken{
+		{Name: "dashboard", Token: "read-secret", Scope: types.ScopeRead},
+		{Name: "ci", Token: "operator-secret", Scope: types.ScopeOperator},
+	}
+
+	tests := []struct {
+		name          string
+		config        types.APIConfig
+		privateSocket bool
+		header        string
+		want          string // Expected scope, empty when the caller is rejected
+	}{
+		{"anonymous on a private unix socket", types.APIConfig{Listen: "unix:///run/crontask.sock"}, true, "", types.ScopeOperator},
+		{"anonymous on a shared unix socket", types.APIConfig{Listen: "unix:///tmp/crontask.sock"}, false, "", types.ScopeRead},
+		{"anonymous over tcp", types.APIConfig{Listen: "0.0.0.0:8080"}, false, "", types.ScopeRead},
+		{"read token", types.APIConfig{Listen: "0.0.0.0:8080", Tokens: tokens}, false, "Bearer read-secret", types.ScopeRead},
+		{"operator token", types.APIConfig{Listen: "0.0.0.0:8080", Tokens: tokens}, false, "Bearer operator-secret", types.ScopeOperator},
+		{"wrong token", types.APIConfig{Listen: "0.0.0.0:8080", Tokens: tokens}, false, "Bearer guess", ""},
+		{"wrong scheme", types.APIConfig{Listen: "0.0.0.0:8080", Tokens: tokens}, false, "Basic b3A6b3A=", ""},
+		{"missing credentials", types.APIConfig{Listen: "unix:///run/crontask.sock", Tokens: tokens}, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{config: &tt.config, privateSocket: tt.privateSocket}
+
+			r := httptest.NewRequest("GET", "/jobs", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			id, err := s.authenticate(r)
+			switch {
+			case tt.want == "" && err == nil:
+				t.Fatalf("authenticate = %s (%s), want an error", id.name, id.scope)
+			case tt.want != "" && err != nil:
+				t.Fatalf("authenticate = %v, want scope %s", err, tt.want)
+			case tt.want != "" && id.scope != tt.want:
+				t.Errorf("scope = %s, want %s", id.scope, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivateDir(t *testing.T) {
+	private := t.TempDir()
+	if err := os.Chmod(private, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	shared := t.TempDir()
+	if err := os.Chmod(shared, 0o1777); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{private, true},
+		{shared, false},
+		{filepath.Join(private, "missing"), false},
+	}
+
+	for _, tt := range tests {
+		if got := privateDir(tt.dir); got != tt.want {
+			t.Errorf("privateDir(%s) = %t, want %t", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultListen(t *testing.T) {
+	if os.Geteuid() == 0 {
+		address, err := DefaultListen()
+		if err != nil || address != "unix:///run/crontask.sock" {
+			t.Errorf("DefaultListen() = %q, %v, want unix:///run/crontask.sock", address, err)
+		}
+		return
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if address, err := DefaultListen(); err != nil || address != "unix:///run/user/1000/crontask.sock" {
+		t.Errorf("DefaultListen() = %q, %v, want the runtime directory", address, err)
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	if address, err := DefaultListen(); err == nil {
+		t.Errorf("DefaultListen() = %q without XDG_RUNTIME_DIR, want an error", address)
+	}
+}
@@ -0,0 +1,124 @@
+// internal/api/client.go
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+// Client talks to a running daemon's management API
+type Client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+}
+
+// NewClient connects to the API described by cfg. Over TLS the daemon's
+// own certificate is trusted, so a local client needs no extra CA.
+func NewClient(cfg *types.APIConfig, token string) (*Client, error) {
+	address := cfg.Listen
+	if address == "" {
+		var err error
+		if address, err = DefaultListen(); err != nil {
+			return nil, err
+		}
+	}
+
+	transport := &http.Transport{}
+	scheme := "http"
+	host := address
+
+	if path, ok := strings.CutPrefix(address, unixPrefix); ok {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+		host = "crontask"
+	}
+
+	if cfg.TLS.CertFile != "" {
+		pem, err := os.ReadFile(cfg.TLS.CertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read api certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(pem)
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		scheme = "https"
+	}
+
+	return &Client{
+		http:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		baseURL: scheme + "://" + host,
+		token:   token,
+	}, nil
+}
+
+// ListJobs returns the registered jobs
+func (c *Client) ListJobs() ([]map[string]interface{}, error) {
+	var jobs []map[string]interface{}
+	err := c.do(http.MethodGet, "/jobs", &jobs)
+	return jobs, err
+}
+
+// TriggerJob runs a job now and returns the run ID
+func (c *Client) TriggerJob(id string) (string, error) {
+	var body map[string]string
+	if err := c.do(http.MethodPost, "/jobs/"+url.PathEscape(id)+"/trigger", &body); err != nil {
+		return "", err
+	}
+	return body["run_id"], nil
+}
+
+// JobHistory returns a job's most recent runs, newest first
+func (c *Client) JobHistory(id string, limit int) ([]store.RunRecord, error) {
+	var records []store.RunRecord
+	path := "/jobs/" + url.PathEscape(id) + "/history?limit=" + strconv.Itoa(limit)
+	err := c.do(http.MethodGet, path, &records)
+	return records, err
+}
+
+func (c *Client) do(method string, path string, out interface{}) error {
+	req, err := http.NewRequest(method, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var body map[string]string
+		if json.Unmarshal(data, &body) == nil && body["error"] != "" {
+			return fmt.Errorf("%s", body["error"])
+		}
+		return fmt.Errorf("daemon answered %s", resp.Status)
+	}
+
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,146 @@
+// internal/api/handlers.go
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/amir-mohammad-HP/crontask/internal/metrics"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/internal/worker"
+)
+
+// Runs returned by the history endpoint when no limit is given
+const defaultHistoryLimit = 20
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /jobs", s.require(types.ScopeRead, s.listJobs))
+	mux.HandleFunc("GET /jobs/{id}", s.require(types.ScopeRead, s.getJob))
+	mux.HandleFunc("POST /jobs/{id}/trigger", s.require(types.ScopeOperator, s.triggerJob))
+	mux.HandleFunc("POST /jobs/{id}/pause", s.require(types.ScopeOperator, s.pauseJob))
+	mux.HandleFunc("POST /jobs/{id}/resume", s.require(types.ScopeOperator, s.resumeJob))
+	mux.HandleFunc("GET /jobs/{id}/history", s.require(types.ScopeRead, s.jobHistory))
+	mux.HandleFunc("GET /status", s.require(types.ScopeRead, s.status))
+	mux.Handle("GET /metrics", s.require(types.ScopeRead, metrics.Handler().ServeHTTP))
+
+	// Probes stay open so orchestrators need no credentials
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /readyz", s.readyz)
+
+	return mux
+}
+
+func (s *Server) listJobs(rw http.ResponseWriter, r *http.Request) {
+	writeJSON(rw, http.StatusOK, s.worker.ListJobs())
+}
+
+func (s *Server) getJob(rw http.ResponseWriter, r *http.Request) {
+	job, err := s.worker.GetJob(r.PathValue("id"))
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	writeJSON(rw, http.StatusOK, job)
+}
+
+func (s *Server) triggerJob(rw http.ResponseWriter, r *http.Request) {
+	runID, err := s.worker.TriggerJob(r.PathValue("id"))
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	writeJSON(rw, http.StatusAccepted, map[string]string{"run_id": runID})
+}
+
+func (s *Server) pauseJob(rw http.ResponseWriter, r *http.Request) {
+	if err := s.worker.PauseJob(r.PathValue("id")); err != nil {
+		writeError(rw, err)
+		return
+	}
+	s.getJob(rw, r)
+}
+
+func (s *Server) resumeJob(rw http.ResponseWriter, r *http.Request) {
+	if err := s.worker.ResumeJob(r.PathValue("id")); err != nil {
+		writeError(rw, err)
+		return
+	}
+	s.getJob(rw, r)
+}
+
+func (s *Server) jobHistory(rw http.ResponseWriter, r *http.Request) {
+	limit := defaultHistoryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			writeJSON(rw, http.StatusBadRequest, errorBody("invalid limit "+strconv.Quote(value)))
+			return
+		}
+		limit = n
+	}
+
+	records, err := s.worker.JobHistory(r.PathValue("id"), limit)
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	if records == nil {
+		records = []store.RunRecord{}
+	}
+	writeJSON(rw, http.StatusOK, records)
+}
+
+func (s *Server) status(rw http.ResponseWriter, r *http.Request) {
+	writeJSON(rw, http.StatusOK, s.worker.Status())
+}
+
+func (s *Server) healthz(rw http.ResponseWriter, r *http.Request) {
+	writeHealth(rw, s.worker.Liveness())
+}
+
+func (s *Server) readyz(rw http.ResponseWriter, r *http.Request) {
+	writeHealth(rw, s.worker.Readiness(r.Context()))
+}
+
+// writeHealth answers 200 when every check passes, 503 otherwise
+func writeHealth(rw http.ResponseWriter, checks []worker.HealthCheck) {
+	status, code := "ok", http.StatusOK
+	for _, check := range checks {
+		if !check.OK {
+			status, code = "failing", http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	writeJSON(rw, code, map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+func writeJSON(rw http.ResponseWriter, status int, body interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(body)
+}
+
+// writeError maps worker errors to HTTP statuses
+func writeError(rw http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, worker.ErrJobNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, store.ErrNoStore):
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(rw, status, errorBody(err.Error()))
+}
+
+func errorBody(message string) map[string]string {
+	return map[string]string{"error": message}
+}
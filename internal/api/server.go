@@ -0,0 +1,166 @@
+// internal/api/server.go
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/internal/worker"
+	"github.com/amir-mohammad-HP/crontask/pkg/logger"
+)
+
+const unixPrefix = "unix://"
+
+// Server exposes the worker over HTTP
+type Server struct {
+	config  *types.APIConfig
+	logger  *logger.StdLogger
+	worker  *worker.Worker
+	server  *http.Server
+	address string // Where the API listens, set by Start
+
+	// Listening on a Unix socket in a directory only the daemon's user can
+	// write to, whose permissions then guard every caller
+	privateSocket bool
+}
+
+func New(cfg *types.APIConfig, w *worker.Worker, logger *logger.StdLogger) *Server {
+	s := &Server{
+		config: cfg,
+		logger: logger,
+		worker: w,
+	}
+
+	s.server = &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Start listens on the configured address and serves in the background
+func (s *Server) Start(wg *sync.WaitGroup) error {
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	address := s.config.Listen
+	if address == "" {
+		if address, err = DefaultListen(); err != nil {
+			return err
+		}
+	}
+
+	listener, err := Listen(address)
+	if err != nil {
+		return err
+	}
+	s.address = address
+	if path, ok := strings.CutPrefix(address, unixPrefix); ok {
+		s.privateSocket = privateDir(filepath.Dir(path))
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	if !s.authEnabled() && !s.privateSocket {
+		s.logger.Warn("Management API has no authentication, callers on %s get read access only", s.address)
+	}
+
+	s.logger.Info("Management API listening | %s: %s, %s: %t, %s: %t",
+		"address", s.address,
+		"tls", tlsConfig != nil,
+		"auth", s.authEnabled())
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Management API stopped | %s", err.Error())
+		}
+	}()
+
+	return nil
+}
+
+// Stop closes the listener and waits briefly for in-flight requests
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// DefaultListen returns the local control socket the daemon and the CLI
+// agree on when api.listen is empty: /run/crontask.sock for root and
+// $XDG_RUNTIME_DIR/crontask.sock otherwise. Shared directories like /tmp
+// are never used, another user could take the path over.
+func DefaultListen() (string, error) {
+	if os.Geteuid() == 0 {
+		return unixPrefix + "/run/crontask.sock", nil
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return unixPrefix + filepath.Join(dir, "crontask.sock"), nil
+	}
+	return "", fmt.Errorf("no local control socket: XDG_RUNTIME_DIR is not set, set api.listen")
+}
+
+// Listen opens a TCP address or, with a unix:// prefix, a Unix socket
+func Listen(address string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(address, unixPrefix); ok {
+		if err := removeStaleSocket(path); err != nil {
+			return nil, err
+		}
+
+		// Created as 0660, there is no window with looser permissions
+		listener, err := listenUnix(path, 0o660)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+		}
+		return listener, nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	return listener, nil
+}
+
+// removeStaleSocket removes a socket left behind by a previous run, which
+// would block the bind. Anything else at path, or a socket another daemon
+// still answers on, is left alone.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use, is another daemon running?", path)
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+	return nil
+}
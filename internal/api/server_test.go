@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenUnix(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("new socket", func(t *testing.T) {
+		path := filepath.Join(dir, "new.sock")
+		listener, err := Listen(unixPrefix + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o660 {
+			t.Errorf("socket permissions = %o, want 660", perm)
+		}
+	})
+
+	t.Run("stale socket is replaced", func(t *testing.T) {
+		path := filepath.Join(dir, "stale.sock")
+		stale, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		stale.Close()
+
+		listener, err := Listen(unixPrefix + path)
+		if err != nil {
+			t.Fatalf("Listen over a stale socket = %v", err)
+		}
+		listener.Close()
+	})
+
+	t.Run("socket in use is kept", func(t *testing.T) {
+		path := filepath.Join(dir, "busy.sock")
+		busy, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer busy.Close()
+
+		if listener, err := Listen(unixPrefix + path); err == nil {
+			listener.Close()
+			t.Fatal("Listen took over a socket another process listens on")
+		}
+		if conn, err := net.Dial("unix", path); err != nil {
+			t.Errorf("the running socket no longer answers: %v", err)
+		} else {
+			conn.Close()
+		}
+	})
+
+	t.Run("regular file is kept", func(t *testing.T) {
+		path := filepath.Join(dir, "file.sock")
+		if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if listener, err := Listen(unixPrefix + path); err == nil {
+			listener.Close()
+			t.Fatal("Listen replaced a regular file")
+		}
+		if data, err := os.ReadFile(path); err != nil || string(data) != "data" {
+			t.Errorf("file changed: %q, %v", data, err)
+		}
+	})
+}
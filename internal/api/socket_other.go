@@ -0,0 +1,19 @@
+//go:build !unix
+
+// internal/api/socket_other.go
+package api
+
+import (
+	"net"
+	"os"
+)
+
+// listenUnix creates a Unix socket, its permissions are left to the system
+func listenUnix(path string, perm os.FileMode) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
+
+// privateDir cannot check ownership here, so no directory counts as private
+func privateDir(dir string) bool {
+	return false
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+// internal/api/socket_unix.go
+package api
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// privateDir reports whether dir belongs to the current user, or root, and
+// no one else can create or replace files in it
+func privateDir(dir string) bool {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	if int(stat.Uid) != os.Geteuid() && stat.Uid != 0 {
+		return false
+	}
+	return info.Mode().Perm()&0o022 == 0
+}
+
+// listenUnix creates a Unix socket with the given permissions. The umask is
+// narrowed while binding, so the socket is never reachable with the
+// default permissions.
+func listenUnix(path string, perm os.FileMode) (net.Listener, error) {
+	old := syscall.Umask(int(^perm & 0o777))
+	defer syscall.Umask(old)
+
+	return net.Listen("unix", path)
+}
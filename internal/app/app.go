@@ -3,8 +3,11 @@ package app
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/amir-mohammad-HP/crontask/internal/api"
 	"github.com/amir-mohammad-HP/crontask/internal/signals"
+	"github.com/amir-mohammad-HP/crontask/internal/tracing"
 	"github.com/amir-mohammad-HP/crontask/internal/types"
 	"github.com/amir-mohammad-HP/crontask/internal/worker"
 	"github.com/amir-mohammad-HP/crontask/pkg/logger"
@@ -15,19 +18,27 @@ type App struct {
 	config        *types.Config
 	logger        *logger.StdLogger
 	worker        *worker.Worker
+	api           *api.Server
 	shutdown      *shutdown.Manager
 	signalHandler *signals.Handler
 	wg            sync.WaitGroup
 }
 
 func New(cfg *types.Config, logger *logger.StdLogger) *App {
-	return &App{
+	a := &App{
 		config:        cfg,
 		logger:        logger,
 		worker:        worker.New(cfg, logger),
 		shutdown:      shutdown.NewManager(logger),
 		signalHandler: signals.NewHandler(logger),
 	}
+
+	// Management API is optional
+	if cfg.API.Enabled {
+		a.api = api.New(&cfg.API, a.worker, logger)
+	}
+
+	return a
 }
 
 func (a *App) Run() error {
@@ -42,15 +53,38 @@ func (a *App) Run() error {
 		a.shutdown.Initiate()
 	})
 
-	// Register cleanup tasks
-	a.shutdown.RegisterTask("worker", a.worker.Stop)
-	a.shutdown.RegisterTask("application", a.cleanup)
+	// Export traces if enabled
+	stopTracing, err := tracing.Setup(ctx, &a.config.Tracing)
+	if err != nil {
+		return err
+	}
 
 	// Start worker
 	if err := a.worker.Start(ctx, &a.wg); err != nil {
 		return err
 	}
 
+	// Start management API
+	if a.api != nil {
+		if err := a.api.Start(&a.wg); err != nil {
+			return err
+		}
+	}
+
+	// Register cleanup tasks, run in this order: the API stops serving
+	// before the worker drains its runs and closes the store, and traces
+	// are flushed last
+	if a.api != nil {
+		a.shutdown.RegisterTask("api", a.api.Stop)
+	}
+	a.shutdown.RegisterTask("worker", a.worker.Stop)
+	a.shutdown.RegisterTask("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return stopTracing(ctx)
+	})
+	a.shutdown.RegisterTask("application", a.cleanup)
+
 	a.logger.Debug("app | wait to catch shutdown signal")
 	<-a.shutdown.Done()
 	a.shutdown.Wait(ctx)
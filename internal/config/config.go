@@ -22,12 +22,53 @@ var defaultConfig = types.Config{
 		Interval:      5 * time.Second,
 		MaxJobs:       10,
 		RetryAttempts: 3,
+		DryRun:        false,
+	},
+	Scheduler: types.SchedulerConfig{
+		Timezone:    "Local",
+		Blackouts:   []string{},
+		LockPolicy:  "wait",
+		LockTimeout: 10 * time.Minute,
+
+		CatchUp:       "ignore",
+		CatchUpWindow: 24 * time.Hour,
+		CatchUpLimit:  10,
+
+		InterruptPolicy: "ignore",
 	},
 	Docker: types.DockerConfig{
 		Enabled:      true,
 		SocketPath:   "/var/run/docker.sock",
 		PollInterval: 5 * time.Second,
 		LabelPrefix:  "crontask.",
+		Exec: types.ExecOptions{
+			Shell:    "sh",
+			Template: "off",
+			Env:      []string{},
+		},
+		AllowPrivileged: false,
+	},
+	Store: types.StoreConfig{
+		Enabled:     true,
+		Path:        "",
+		MaxRuns:     10000,
+		MaxAge:      30 * 24 * time.Hour,
+		OutputLimit: 4096,
+	},
+	API: types.APIConfig{
+		Enabled: false,
+		Listen:  "",
+		Tokens:  []types.APIToken{},
+		TLS: types.APITLSConfig{
+			ClientScope: "read",
+		},
+	},
+	Tracing: types.TracingConfig{
+		Enabled:     false,
+		Endpoint:    "localhost:4318",
+		Insecure:    true,
+		ServiceName: "crontask",
+		SampleRatio: 1,
 	},
 	Shutdown: types.ShutdownConfig{
 		Timeout: 30 * time.Second,
@@ -113,12 +154,54 @@ func SetDefaults() {
 	viper.SetDefault("worker.interval", defaultConfig.Worker.Interval)
 	viper.SetDefault("worker.max_jobs", defaultConfig.Worker.MaxJobs)
 	viper.SetDefault("worker.retry_attempts", defaultConfig.Worker.RetryAttempts)
+	viper.SetDefault("worker.dry_run", defaultConfig.Worker.DryRun)
+
+	// Scheduler configuration defaults
+	viper.SetDefault("scheduler.timezone", defaultConfig.Scheduler.Timezone)
+	viper.SetDefault("scheduler.blackouts", defaultConfig.Scheduler.Blackouts)
+	viper.SetDefault("scheduler.lock_policy", defaultConfig.Scheduler.LockPolicy)
+	viper.SetDefault("scheduler.lock_timeout", defaultConfig.Scheduler.LockTimeout)
+	viper.SetDefault("scheduler.catchup", defaultConfig.Scheduler.CatchUp)
+	viper.SetDefault("scheduler.catchup_window", defaultConfig.Scheduler.CatchUpWindow)
+	viper.SetDefault("scheduler.catchup_limit", defaultConfig.Scheduler.CatchUpLimit)
+	viper.SetDefault("scheduler.interrupt_policy", defaultConfig.Scheduler.InterruptPolicy)
 
 	// Docker configuration defaults
 	viper.SetDefault("docker.enabled", defaultConfig.Docker.Enabled)
 	viper.SetDefault("docker.socket_path", defaultConfig.Docker.SocketPath)
 	viper.SetDefault("docker.poll_interval", defaultConfig.Docker.PollInterval)
 	viper.SetDefault("docker.label_prefix", defaultConfig.Docker.LabelPrefix)
+	viper.SetDefault("docker.exec.user", defaultConfig.Docker.Exec.User)
+	viper.SetDefault("docker.exec.shell", defaultConfig.Docker.Exec.Shell)
+	viper.SetDefault("docker.exec.template", defaultConfig.Docker.Exec.Template)
+	viper.SetDefault("docker.exec.workdir", defaultConfig.Docker.Exec.WorkDir)
+	viper.SetDefault("docker.exec.env", defaultConfig.Docker.Exec.Env)
+	viper.SetDefault("docker.exec.privileged", defaultConfig.Docker.Exec.Privileged)
+	viper.SetDefault("docker.exec.tty", defaultConfig.Docker.Exec.Tty)
+	viper.SetDefault("docker.allow_privileged", defaultConfig.Docker.AllowPrivileged)
+
+	// Store configuration defaults
+	viper.SetDefault("store.enabled", defaultConfig.Store.Enabled)
+	viper.SetDefault("store.path", defaultConfig.Store.Path)
+	viper.SetDefault("store.max_runs", defaultConfig.Store.MaxRuns)
+	viper.SetDefault("store.max_age", defaultConfig.Store.MaxAge)
+	viper.SetDefault("store.output_limit", defaultConfig.Store.OutputLimit)
+
+	// API configuration defaults
+	viper.SetDefault("api.enabled", defaultConfig.API.Enabled)
+	viper.SetDefault("api.listen", defaultConfig.API.Listen)
+	viper.SetDefault("api.tokens", defaultConfig.API.Tokens)
+	viper.SetDefault("api.tls.cert_file", defaultConfig.API.TLS.CertFile)
+	viper.SetDefault("api.tls.key_file", defaultConfig.API.TLS.KeyFile)
+	viper.SetDefault("api.tls.client_ca_file", defaultConfig.API.TLS.ClientCAFile)
+	viper.SetDefault("api.tls.client_scope", defaultConfig.API.TLS.ClientScope)
+
+	// Tracing configuration defaults
+	viper.SetDefault("tracing.enabled", defaultConfig.Tracing.Enabled)
+	viper.SetDefault("tracing.endpoint", defaultConfig.Tracing.Endpoint)
+	viper.SetDefault("tracing.insecure", defaultConfig.Tracing.Insecure)
+	viper.SetDefault("tracing.service_name", defaultConfig.Tracing.ServiceName)
+	viper.SetDefault("tracing.sample_ratio", defaultConfig.Tracing.SampleRatio)
 
 	// Shutdown configuration defaults
 	viper.SetDefault("shutdown.timeout", defaultConfig.Shutdown.Timeout)
@@ -141,20 +224,30 @@ func SetDefaults() {
 
 // Load loads configuration from file, environment variables, or defaults
 func Load() (*types.Config, error) {
+	return LoadFrom("")
+}
+
+// LoadFrom loads configuration like Load, reading the given file instead of
+// searching the config paths when file is not empty
+func LoadFrom(file string) (*types.Config, error) {
 	viper.SetConfigName("crontaskd") // Name of config file (without extension)
 	viper.SetConfigType("yaml")      // REQUIRED if the config file does not have the extension in the name
 
 	// Set all default values
 	SetDefaults()
 
-	// Add configuration paths
-	configPaths, err := getConfigPaths()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get config paths: %w", err)
-	}
+	if file != "" {
+		viper.SetConfigFile(file)
+	} else {
+		// Add configuration paths
+		configPaths, err := getConfigPaths()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config paths: %w", err)
+		}
 
-	for _, path := range configPaths {
-		viper.AddConfigPath(filepath.Dir(path))
+		for _, path := range configPaths {
+			viper.AddConfigPath(filepath.Dir(path))
+		}
 	}
 
 	// Try to read configuration file
@@ -196,13 +289,17 @@ func CreateDefaultConfig() error {
 		return err
 	}
 
+	return CreateDefaultConfigAt(filepath.Join(systemConfigDir, "crontaskd.yaml"))
+}
+
+// CreateDefaultConfigAt writes the default configuration to configPath,
+// refusing to overwrite an existing file
+func CreateDefaultConfigAt(configPath string) error {
 	// Create directory if it doesn't exist
-	if err := os.MkdirAll(systemConfigDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configPath := filepath.Join(systemConfigDir, "crontaskd.yaml")
-
 	// Check if file already exists
 	if _, err := os.Stat(configPath); err == nil {
 		return errors.New("config file already exists")
@@ -12,12 +12,56 @@ worker:
   interval: 10s
   max_jobs: 50
   retry_attempts: 5
+  dry_run: false      # Log and record runs without executing anything
+
+scheduler:
+  timezone: "Local"  # IANA zone (e.g. "Europe/Berlin"), "Local" uses the host zone
+  blackouts: []      # e.g. ["Sun 02:00-04:00", "2026-01-02T22:00:00Z/2026-01-03T02:00:00Z"]
+  lock_policy: "wait"  # wait or skip when a job's lock group is busy
+  lock_timeout: 10m    # 0 waits forever
+  catchup: "ignore"    # Runs missed while down: ignore, once or all
+  catchup_window: 24h  # Only runs missed within this window are caught up
+  catchup_limit: 10    # Most missed runs replayed by "all"
+  interrupt_policy: "ignore"  # ignore or rerun jobs cut short by a restart
 
 docker:
   enabled: true
   socket_path: "/var/run/docker.sock"
   poll_interval: 5s
   label_prefix: "crontask."
+  exec:               # Defaults for every job's exec, job labels override them
+    user: ""          # user, uid, user:group or uid:gid, the image's user if empty
+    shell: "sh"       # sh, bash, ash or an absolute path, runs tasks that are not JSON arrays
+    template: "off"   # off, on or strict, opt in to render tasks as Go templates
+    workdir: ""       # Absolute path, the image's workdir if empty
+    env: []           # e.g. ["TZ=UTC"]
+    privileged: false
+    tty: false
+  allow_privileged: false  # Honour privileged=true on job labels
+
+store:
+  enabled: true
+  path: "/var/lib/crontask/crontask.db"  # Auto-detected if empty
+  max_runs: 10000     # Run records kept
+  max_age: 720h       # 30 days
+  output_limit: 4096  # Bytes of task output stored per run
+
+api:
+  enabled: false
+  listen: ""  # host:port or unix:///path, the local control socket used by the CLI if empty
+  tokens: []  # e.g. [{name: "ci", token: "<secret>", scope: "operator"}], scopes: read, operator
+  tls:
+    cert_file: ""
+    key_file: ""
+    client_ca_file: ""   # Require client certificates signed by this CA
+    client_scope: "read" # Scope of callers identified by certificate
+
+tracing:
+  enabled: false
+  endpoint: "localhost:4318"  # OTLP over HTTP
+  insecure: true              # Plain HTTP to the collector
+  service_name: "crontask"
+  sample_ratio: 1.0           # Fraction of runs traced
 
 shutdown:
   timeout: 60s
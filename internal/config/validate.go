@@ -0,0 +1,138 @@
+// internal/config/validate.go
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amir-mohammad-HP/crontask/internal/schedule"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+)
+
+// Validate checks a loaded configuration and returns every problem found
+func Validate(cfg *types.Config) []error {
+	var errs []error
+	fail := func(key string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	switch strings.ToLower(cfg.Logger.Level) {
+	case "debug", "info", "warn", "warning", "error", "fatal":
+	default:
+		fail("logger.level", "invalid level %q", cfg.Logger.Level)
+	}
+
+	if cfg.Worker.RetryAttempts < 0 {
+		fail("worker.retry_attempts", "must not be negative")
+	}
+
+	if _, err := schedule.LoadLocation(cfg.Scheduler.Timezone); err != nil {
+		fail("scheduler.timezone", "%s", err.Error())
+	}
+	for _, spec := range cfg.Scheduler.Blackouts {
+		if _, err := schedule.ParseWindow(spec); err != nil {
+			fail("scheduler.blackouts", "%s", err.Error())
+		}
+	}
+	if p := cfg.Scheduler.LockPolicy; p != "wait" && p != "skip" {
+		fail("scheduler.lock_policy", "invalid policy %q, expected wait or skip", p)
+	}
+	if cfg.Scheduler.LockTimeout < 0 {
+		fail("scheduler.lock_timeout", "must not be negative")
+	}
+	switch cfg.Scheduler.CatchUp {
+	case types.CatchUpIgnore, types.CatchUpOnce, types.CatchUpAll:
+	default:
+		fail("scheduler.catchup", "invalid policy %q, expected ignore, once or all", cfg.Scheduler.CatchUp)
+	}
+	if cfg.Scheduler.CatchUpWindow < 0 {
+		fail("scheduler.catchup_window", "must not be negative")
+	}
+	if cfg.Scheduler.CatchUpLimit < 1 {
+		fail("scheduler.catchup_limit", "must be at least 1")
+	}
+	if p := cfg.Scheduler.InterruptPolicy; p != types.InterruptIgnore && p != types.InterruptRerun {
+		fail("scheduler.interrupt_policy", "invalid policy %q, expected ignore or rerun", p)
+	}
+
+	if cfg.Docker.Enabled {
+		if cfg.Docker.LabelPrefix == "" {
+			fail("docker.label_prefix", "must not be empty")
+		}
+		if cfg.Docker.PollInterval <= 0 {
+			fail("docker.poll_interval", "must be positive")
+		}
+		if err := docker.ValidateExecOptions(cfg.Docker.Exec); err != nil {
+			fail("docker.exec", "%s", err.Error())
+		}
+		if cfg.Docker.Exec.Privileged && !cfg.Docker.AllowPrivileged {
+			fail("docker.exec.privileged", "needs docker.allow_privileged")
+		}
+	}
+
+	if cfg.Store.MaxRuns < 0 {
+		fail("store.max_runs", "must not be negative")
+	}
+	if cfg.Store.MaxAge < 0 {
+		fail("store.max_age", "must not be negative")
+	}
+	if cfg.Store.OutputLimit < 0 {
+		fail("store.output_limit", "must not be negative")
+	}
+
+	errs = append(errs, validateAPI(&cfg.API)...)
+
+	if cfg.Tracing.Enabled && cfg.Tracing.Endpoint == "" {
+		fail("tracing.endpoint", "must not be empty")
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		fail("tracing.sample_ratio", "must be between 0 and 1")
+	}
+
+	return errs
+}
+
+// validateAPI checks the listen address, tokens and TLS files of the API
+func validateAPI(cfg *types.APIConfig) []error {
+	var errs []error
+	fail := func(key string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	seen := make(map[string]bool)
+	for i, token := range cfg.Tokens {
+		key := fmt.Sprintf("api.tokens[%d]", i)
+		if token.Name == "" {
+			fail(key, "missing name")
+		}
+		if token.Token == "" {
+			fail(key, "missing token")
+		} else if seen[token.Token] {
+			fail(key, "token is not unique")
+		}
+		seen[token.Token] = true
+		if !validScope(token.Scope) {
+			fail(key, "invalid scope %q, expected read or operator", token.Scope)
+		}
+	}
+
+	tls := cfg.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		fail("api.tls", "cert_file and key_file must be set together")
+	}
+	if tls.ClientCAFile != "" {
+		if tls.CertFile == "" {
+			fail("api.tls.client_ca_file", "needs cert_file and key_file")
+		}
+		if !validScope(tls.ClientScope) {
+			fail("api.tls.client_scope", "invalid scope %q, expected read or operator", tls.ClientScope)
+		}
+	}
+
+	return errs
+}
+
+func validScope(scope string) bool {
+	return scope == types.ScopeRead || scope == types.ScopeOperator
+}
@@ -0,0 +1,233 @@
+// internal/job/dependency.go
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dependency conditions, declared on the downstream job
+const (
+	AfterAny     = "after"      // Run once the upstream job finished, whatever the outcome
+	AfterSuccess = "on_success" // Run only when the upstream job succeeded
+	AfterFailure = "on_failure" // Run only when the upstream job failed
+)
+
+// Dependency links a job to the upstream job it follows. Ref is a job name,
+// resolved in the job's own container first, or container/name.
+type Dependency struct {
+	Ref       string
+	Condition string
+}
+
+// ChainStep is a job of a chain together with its upstream jobs in the chain
+type ChainStep struct {
+	Job      *DockerJob
+	Upstream []ChainEdge
+}
+
+// ChainEdge is a resolved dependency
+type ChainEdge struct {
+	Job       *DockerJob
+	Condition string
+}
+
+// ParseDependencies turns comma-separated after, on_success and on_failure
+// lists into dependencies
+func ParseDependencies(after string, onSuccess string, onFailure string) []Dependency {
+	var deps []Dependency
+	for condition, refs := range map[string]string{
+		AfterAny:     after,
+		AfterSuccess: onSuccess,
+		AfterFailure: onFailure,
+	} {
+		for _, ref := range strings.Split(refs, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				deps = append(deps, Dependency{Ref: ref, Condition: condition})
+			}
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Ref != deps[j].Ref {
+			return deps[i].Ref < deps[j].Ref
+		}
+		return deps[i].Condition < deps[j].Condition
+	})
+	return deps
+}
+
+// Satisfied reports whether an upstream outcome lets the dependent run
+func (d ChainEdge) Satisfied(err error) bool {
+	switch d.Condition {
+	case AfterSuccess:
+		return err == nil
+	case AfterFailure:
+		return err != nil
+	default:
+		return true
+	}
+}
+
+// Resolve finds the job a dependency of from refers to
+func (jr *JobRegistry) Resolve(from *DockerJob, ref string) (*DockerJob, error) {
+	jr.mu.RLock()
+	defer jr.mu.RUnlock()
+
+	return jr.resolve(from, ref)
+}
+
+func (jr *JobRegistry) resolve(from *DockerJob, ref string) (*DockerJob, error) {
+	// container/name
+	if strings.Contains(ref, "/") {
+		for _, job := range jr.jobs {
+			if job.Key() == ref {
+				return job, nil
+			}
+		}
+		return nil, fmt.Errorf("dependency %q not found", ref)
+	}
+
+	// Same container first
+	var matches []*DockerJob
+	for _, job := range jr.jobs {
+		if job.name != ref {
+			continue
+		}
+		if job.containerID == from.containerID {
+			return job, nil
+		}
+		matches = append(matches, job)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("dependency %q not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("dependency %q is ambiguous, use container/name", ref)
+	}
+}
+
+// CheckDependencies resolves the dependencies of job, returning the ones
+// that are not registered (yet) and an error if they form a cycle
+func (jr *JobRegistry) CheckDependencies(job *DockerJob) ([]string, error) {
+	jr.mu.RLock()
+	defer jr.mu.RUnlock()
+
+	var missing []string
+	for _, dep := range job.dependencies {
+		if _, err := jr.resolve(job, dep.Ref); err != nil {
+			missing = append(missing, err.Error())
+		}
+	}
+
+	// Walk upstream from job, coming back to it means a cycle
+	path := []string{job.Key()}
+	visited := make(map[*DockerJob]bool)
+
+	var walk func(current *DockerJob) error
+	walk = func(current *DockerJob) error {
+		for _, dep := range current.dependencies {
+			upstream, err := jr.resolve(current, dep.Ref)
+			if err != nil {
+				continue
+			}
+
+			path = append(path, upstream.Key())
+			if upstream == job {
+				return fmt.Errorf("dependency cycle: %s", strings.Join(path, " -> "))
+			}
+			if !visited[upstream] {
+				visited[upstream] = true
+				if err := walk(upstream); err != nil {
+					return err
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		return nil
+	}
+
+	return missing, walk(job)
+}
+
+// ChainPlan returns every job downstream of root in an order where each
+// job comes after all of its upstream jobs in the chain
+func (jr *JobRegistry) ChainPlan(root *DockerJob) []ChainStep {
+	jr.mu.RLock()
+	defer jr.mu.RUnlock()
+
+	// Resolve upstream edges of every job once
+	upstream := make(map[*DockerJob][]ChainEdge)
+	downstream := make(map[*DockerJob][]*DockerJob)
+	for _, job := range jr.jobs {
+		for _, dep := range job.dependencies {
+			target, err := jr.resolve(job, dep.Ref)
+			if err != nil {
+				continue
+			}
+			upstream[job] = append(upstream[job], ChainEdge{Job: target, Condition: dep.Condition})
+			downstream[target] = append(downstream[target], job)
+		}
+	}
+
+	// Collect everything reachable from root
+	inChain := map[*DockerJob]bool{root: true}
+	queue := []*DockerJob{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range downstream[current] {
+			if !inChain[next] {
+				inChain[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// Topological order, ties broken by key for a stable order
+	var plan []ChainStep
+	done := map[*DockerJob]bool{root: true}
+	for len(done) < len(inChain) {
+		var ready []*DockerJob
+		for job := range inChain {
+			if done[job] {
+				continue
+			}
+
+			blocked := false
+			for _, edge := range upstream[job] {
+				if inChain[edge.Job] && !done[edge.Job] {
+					blocked = true
+					break
+				}
+			}
+			if !blocked {
+				ready = append(ready, job)
+			}
+		}
+
+		// Only a cycle leaves nothing ready, registration rejects those
+		if len(ready) == 0 {
+			break
+		}
+
+		sort.Slice(ready, func(i, j int) bool { return ready[i].Key() < ready[j].Key() })
+		for _, job := range ready {
+			done[job] = true
+
+			step := ChainStep{Job: job}
+			for _, edge := range upstream[job] {
+				if inChain[edge.Job] {
+					step.Upstream = append(step.Upstream, edge)
+				}
+			}
+			plan = append(plan, step)
+		}
+	}
+
+	return plan
+}
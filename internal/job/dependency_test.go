@@ -0,0 +1,221 @@
+package job
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+// testJob builds a chain job; deps are "after" refs, ok and fail the
+// on_success and on_failure ones
+func testJob(container string, name string, after string, ok string, fail string) *DockerJob {
+	return NewDockerJob(types.CronJob{
+		ContainerID:   container + strings.Repeat("0", 12),
+		ContainerName: container,
+		Name:          name,
+		Trigger:       types.TriggerChain,
+		After:         after,
+		OnSuccess:     ok,
+		OnFailure:     fail,
+	}, nil)
+}
+
+func testRegistry(t *testing.T, jobs ...*DockerJob) *JobRegistry {
+	t.Helper()
+	registry := NewJobRegistry(nil)
+	for _, job := range jobs {
+		if !registry.AddJob(job) {
+			t.Fatalf("duplicate job %s", job.Key())
+		}
+	}
+	return registry
+}
+
+func TestParseDependencies(t *testing.T) {
+	got := ParseDependencies(" b, a ,", "c", "a")
+	want := []Dependency{
+		{Ref: "a", Condition: AfterAny},
+		{Ref: "a", Condition: AfterFailure},
+		{Ref: "b", Condition: AfterAny},
+		{Ref: "c", Condition: AfterSuccess},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDependencies = %v, want %v", got, want)
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobs    []*DockerJob
+		check   string // Key of the job to check
+		missing int
+		cycle   string // Expected cycle path, empty for none
+	}{
+		{
+			name:  "linear chain",
+			jobs:  []*DockerJob{testJob("db", "dump", "", "", ""), testJob("db", "upload", "", "dump", "")},
+			check: "db/upload",
+		},
+		{
+			name:  "cross container",
+			jobs:  []*DockerJob{testJob("db", "dump", "", "", ""), testJob("app", "notify", "db/dump", "", "")},
+			check: "app/notify",
+		},
+		{
+			name:    "missing upstream",
+			jobs:    []*DockerJob{testJob("db", "upload", "dump", "", "")},
+			check:   "db/upload",
+			missing: 1,
+		},
+		{
+			name: "ambiguous upstream",
+			jobs: []*DockerJob{
+				testJob("db", "dump", "", "", ""),
+				testJob("cache", "dump", "", "", ""),
+				testJob("app", "notify", "dump", "", ""),
+			},
+			check:   "app/notify",
+			missing: 1,
+		},
+		{
+			name:  "self dependency",
+			jobs:  []*DockerJob{testJob("db", "dump", "dump", "", "")},
+			check: "db/dump",
+			cycle: "db/dump -> db/dump",
+		},
+		{
+			name: "three job cycle",
+			jobs: []*DockerJob{
+				testJob("db", "a", "c", "", ""),
+				testJob("db", "b", "", "a", ""),
+				testJob("db", "c", "", "", "b"),
+			},
+			check: "db/a",
+			cycle: "db/a -> db/c -> db/b -> db/a",
+		},
+		{
+			name: "cycle upstream of the checked job",
+			jobs: []*DockerJob{
+				testJob("db", "a", "b", "", ""),
+				testJob("db", "b", "a", "", ""),
+				testJob("db", "c", "a", "", ""),
+			},
+			check: "db/c",
+		},
+		{
+			name: "diamond",
+			jobs: []*DockerJob{
+				testJob("db", "dump", "", "", ""),
+				testJob("db", "upload", "dump", "", ""),
+				testJob("db", "verify", "dump", "", ""),
+				testJob("db", "notify", "upload,verify", "", ""),
+			},
+			check: "db/notify",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := testRegistry(t, tt.jobs...)
+
+			var checked *DockerJob
+			for _, job := range tt.jobs {
+				if job.Key() == tt.check {
+					checked = job
+				}
+			}
+
+			missing, err := registry.CheckDependencies(checked)
+			if len(missing) != tt.missing {
+				t.Errorf("missing = %v, want %d", missing, tt.missing)
+			}
+
+			switch {
+			case tt.cycle == "" && err != nil:
+				t.Errorf("CheckDependencies = %v, want no cycle", err)
+			case tt.cycle != "" && err == nil:
+				t.Errorf("CheckDependencies = nil, want cycle %s", tt.cycle)
+			case tt.cycle != "" && !strings.HasSuffix(err.Error(), tt.cycle):
+				t.Errorf("CheckDependencies = %v, want cycle %s", err, tt.cycle)
+			}
+		})
+	}
+}
+
+func TestChainPlan(t *testing.T) {
+	dump := testJob("db", "dump", "", "", "")
+	upload := testJob("db", "upload", "", "dump", "")
+	verify := testJob("db", "verify", "dump", "", "")
+	alert := testJob("db", "alert", "", "", "dump")
+	notify := testJob("app", "notify", "db/upload,db/verify", "", "")
+	unrelated := testJob("db", "vacuum", "", "", "")
+	registry := testRegistry(t, dump, upload, verify, alert, notify, unrelated)
+
+	plan := registry.ChainPlan(dump)
+
+	var order []string
+	upstream := make(map[string][]string)
+	for _, step := range plan {
+		order = append(order, step.Job.Key())
+		for _, edge := range step.Upstream {
+			upstream[step.Job.Key()] = append(upstream[step.Job.Key()], edge.Job.Key()+":"+edge.Condition)
+		}
+	}
+
+	wantOrder := []string{"db/alert", "db/upload", "db/verify", "app/notify"}
+	if !reflect.DeepEqual(order, wantOrder) {
+		t.Errorf("plan order = %v, want %v", order, wantOrder)
+	}
+
+	wantUpstream := map[string][]string{
+		"db/alert":   {"db/dump:on_failure"},
+		"db/upload":  {"db/dump:on_success"},
+		"db/verify":  {"db/dump:after"},
+		"app/notify": {"db/upload:after", "db/verify:after"},
+	}
+	if !reflect.DeepEqual(upstream, wantUpstream) {
+		t.Errorf("plan upstream = %v, want %v", upstream, wantUpstream)
+	}
+
+	if plan := registry.ChainPlan(notify); len(plan) != 0 {
+		t.Errorf("ChainPlan of a leaf = %d steps, want none", len(plan))
+	}
+}
+
+func TestChainPlanCycle(t *testing.T) {
+	a := testJob("db", "a", "", "", "")
+	b := testJob("db", "b", "a,c", "", "")
+	c := testJob("db", "c", "b", "", "")
+	registry := testRegistry(t, a, b, c)
+
+	// The cycle blocks both jobs, the plan stops instead of looping
+	if plan := registry.ChainPlan(a); len(plan) != 0 {
+		t.Errorf("ChainPlan = %d steps, want none", len(plan))
+	}
+}
+
+func TestChainEdgeSatisfied(t *testing.T) {
+	failed := errors.New("exit code 1")
+	tests := []struct {
+		condition string
+		err       error
+		want      bool
+	}{
+		{AfterAny, nil, true},
+		{AfterAny, failed, true},
+		{AfterSuccess, nil, true},
+		{AfterSuccess, failed, false},
+		{AfterFailure, nil, false},
+		{AfterFailure, failed, true},
+	}
+
+	for _, tt := range tests {
+		if got := (ChainEdge{Condition: tt.condition}).Satisfied(tt.err); got != tt.want {
+			t.Errorf("%s.Satisfied(%v) = %v, want %v", tt.condition, tt.err, got, tt.want)
+		}
+	}
+}
@@ -2,74 +2,374 @@
 package job
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/amir-mohammad-HP/crontask/internal/schedule"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
 	"github.com/amir-mohammad-HP/crontask/pkg/docker"
 	"github.com/robfig/cron/v3"
 )
 
 type DockerJob struct {
-	id          string
-	containerID string
-	name        string
-	cronExpr    string
-	task        string
-	monitor     *docker.DockerMonitor
-	cronEntryID cron.EntryID
-	lastRun     *time.Time
-	nextRun     time.Time
-}
-
-func NewDockerJob(
-	containerID string,
-	name string,
-	cronExpr string,
-	task string,
-	monitor *docker.DockerMonitor,
-) *DockerJob {
-	return &DockerJob{
-		id:          fmt.Sprintf("%s-%s", containerID[:12], name),
-		containerID: containerID,
-		name:        name,
-		cronExpr:    cronExpr,
-		task:        task,
-		monitor:     monitor,
-	}
-}
-
-func (dj *DockerJob) Execute() error {
-	dj.lastRun = &time.Time{}
-	*dj.lastRun = time.Now()
-
-	output, err := dj.monitor.ExecuteTask(dj.containerID, dj.task)
+	id            string
+	containerID   string
+	containerName string
+	service       string
+	image         string
+	labels        map[string]string
+	name          string
+	trigger       string
+	cronExpr      string
+	resolvedExpr  string
+	timezone      string
+	jitter        time.Duration
+	allow         []*schedule.Window
+	deny          []*schedule.Window
+	at            time.Time
+	startDelay    time.Duration
+	startOnce     bool
+	event         *eventTrigger
+	dependencies  []Dependency
+	lockGroup     string
+	lockPolicy    string
+	lockTimeout   *time.Duration
+	catchUp       string
+	catchUpWindow time.Duration
+	catchUpLimit  int
+	interrupt     string
+	task          string
+	exec          types.ExecOptions
+	monitor       *docker.DockerMonitor
+	cronEntryID   cron.EntryID
+
+	// Run state, written by runs and read by the API
+	stateMu    sync.RWMutex
+	lastRun    *time.Time
+	nextRun    time.Time
+	lastSkip   *time.Time
+	skipReason string
+}
+
+func NewDockerJob(cronJob types.CronJob, monitor *docker.DockerMonitor) *DockerJob {
+	dj := &DockerJob{
+		id:            fmt.Sprintf("%s-%s", cronJob.ContainerID[:12], cronJob.Name),
+		containerID:   cronJob.ContainerID,
+		containerName: cronJob.ContainerName,
+		service:       cronJob.Service,
+		image:         cronJob.Image,
+		labels:        cronJob.Labels,
+		name:          cronJob.Name,
+		trigger:       cronJob.Trigger,
+		cronExpr:      cronJob.CronExpr,
+		resolvedExpr:  cronJob.CronExpr,
+		timezone:      cronJob.Timezone,
+		jitter:        cronJob.Jitter,
+		at:            cronJob.At,
+		startDelay:    cronJob.StartDelay,
+		startOnce:     cronJob.StartOnce,
+		lockGroup:     cronJob.LockGroup,
+		lockPolicy:    cronJob.LockPolicy,
+		lockTimeout:   cronJob.LockTimeout,
+		catchUp:       cronJob.CatchUp,
+		catchUpWindow: cronJob.CatchUpWindow,
+		catchUpLimit:  cronJob.CatchUpLimit,
+		interrupt:     cronJob.Interrupt,
+		task:          cronJob.Task,
+		exec:          cronJob.Exec,
+		monitor:       monitor,
+	}
+
+	// H tokens are resolved from the stable job key, labels were already
+	// validated so an error here is reported when the schedule is parsed
+	if resolved, err := schedule.ResolveHash(cronJob.CronExpr, dj.Key()); err == nil {
+		dj.resolvedExpr = resolved
+	}
+
+	dj.allow, _ = schedule.ParseWindows(cronJob.AllowWindows)
+	dj.deny, _ = schedule.ParseWindows(cronJob.DenyWindows)
+
+	dj.dependencies = ParseDependencies(cronJob.After, cronJob.OnSuccess, cronJob.OnFailure)
+
+	if cronJob.Trigger == types.TriggerEvent {
+		if pattern, err := docker.ParseEventPattern(cronJob.OnEvent, cronJob.EventSelector); err == nil {
+			dj.event = &eventTrigger{pattern: pattern, debounce: cronJob.EventDebounce}
+			if cronJob.EventRate != "" {
+				dj.event.limit, dj.event.per, _ = docker.ParseRateLimit(cronJob.EventRate)
+			}
+		}
+	}
+
+	return dj
+}
+
+// RunContext identifies the run an execution belongs to
+type RunContext struct {
+	ID        string
+	Scheduled time.Time
+	Attempt   int
+}
+
+// Execute runs the job's task for a run, onStart receives the exec ID
+// before it starts
+func (dj *DockerJob) Execute(ctx context.Context, run RunContext, onStart func(execID string)) (*docker.ExecResult, error) {
+	dj.SetLastRun(time.Now())
+
+	return dj.ExecuteStream(ctx, run, onStart, nil, nil)
+}
+
+// ExecuteStream runs the job's task like Execute, streaming its output to
+// stdout and stderr, without touching the job's run state
+func (dj *DockerJob) ExecuteStream(ctx context.Context, run RunContext, onStart func(execID string), stdout, stderr io.Writer) (*docker.ExecResult, error) {
+	task, err := dj.RenderTask(run)
 	if err != nil {
-		return fmt.Errorf("failed to execute task in container %s: %w",
+		return nil, err
+	}
+
+	options := dj.exec
+	options.Env = docker.MergeEnv(dj.exec.Env, dj.RunEnv(run))
+
+	result, err := dj.monitor.ExecuteTaskStream(ctx, dj.containerID, task, options, onStart, stdout, stderr)
+	if err != nil {
+		return result, fmt.Errorf("failed to execute task in container %s: %w",
 			dj.containerID[:12], err)
 	}
 
-	// Log output for debugging
-	if len(output) > 0 {
-		// You might want to log this or store it somewhere
-		_ = output
+	return result, nil
+}
+
+// RunEnv returns the CRONTASK_* variables telling a task which job, run and
+// attempt invoked it
+func (dj *DockerJob) RunEnv(run RunContext) []string {
+	return []string{
+		"CRONTASK_JOB_ID=" + dj.Name(),
+		"CRONTASK_JOB_NAME=" + dj.name,
+		"CRONTASK_JOB_KEY=" + dj.Key(),
+		"CRONTASK_RUN_ID=" + run.ID,
+		"CRONTASK_SCHEDULED_TIME=" + run.Scheduled.Format(time.RFC3339),
+		"CRONTASK_ATTEMPT=" + strconv.Itoa(run.Attempt),
+		"CRONTASK_TRIGGER=" + dj.trigger,
+		"CRONTASK_CONTAINER_ID=" + dj.containerID,
+		"CRONTASK_CONTAINER_NAME=" + dj.containerName,
 	}
+}
+
+// RenderTask returns the task to execute for a run, its template rendered
+// when the job has templates enabled
+func (dj *DockerJob) RenderTask(run RunContext) (string, error) {
+	return docker.RenderTask(dj.task, dj.exec.Template, docker.TaskData{
+		JobID:         dj.Name(),
+		JobName:       dj.name,
+		JobKey:        dj.Key(),
+		Trigger:       dj.trigger,
+		ContainerID:   dj.containerID,
+		ContainerName: dj.containerName,
+		Service:       dj.service,
+		Image:         dj.image,
+		Labels:        dj.labels,
+		RunID:         run.ID,
+		Scheduled:     run.Scheduled,
+		Attempt:       run.Attempt,
+	})
+}
+
+// NewRunID returns a random identifier shared by every job of one run
+func NewRunID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Task returns the command the job runs in its container
+func (dj *DockerJob) Task() string {
+	return dj.task
+}
+
+// ExecOptions returns the user, workdir, environment and flags of the job's exec
+func (dj *DockerJob) ExecOptions() types.ExecOptions {
+	return dj.exec
+}
 
-	return nil
+// JobName returns the job's name as declared in its labels
+func (dj *DockerJob) JobName() string {
+	return dj.name
+}
+
+// LockGroup returns the job's mutual-exclusion group and, when set on the
+// job, its policy and wait timeout. The timeout is nil when not set, an
+// explicit 0 waits forever.
+func (dj *DockerJob) LockGroup() (string, string, *time.Duration) {
+	return dj.lockGroup, dj.lockPolicy, dj.lockTimeout
+}
+
+// CatchUp returns the job's missed-run policy, window and limit as set on
+// the job, empty or zero when the defaults apply
+func (dj *DockerJob) CatchUp() (string, time.Duration, int) {
+	return dj.catchUp, dj.catchUpWindow, dj.catchUpLimit
+}
+
+// InterruptPolicy returns what to do when a run was cut short by a
+// restart, empty when the default applies
+func (dj *DockerJob) InterruptPolicy() string {
+	return dj.interrupt
+}
+
+// Dependencies returns the upstream jobs this job follows
+func (dj *DockerJob) Dependencies() []Dependency {
+	return dj.dependencies
 }
 
 func (dj *DockerJob) Name() string {
-	return fmt.Sprintf("docker-%s", dj.id)
+	return docker.JobID(dj.containerID, dj.name)
 }
 
-func (dj *DockerJob) Schedule() string {
+func (dj *DockerJob) ID() string {
+	return dj.id
+}
+
+// Key identifies the job across container re-creation and daemon restarts
+func (dj *DockerJob) Key() string {
+	return dj.containerName + "/" + dj.name
+}
+
+// Trigger returns what starts the job, one of the types.Trigger* kinds
+func (dj *DockerJob) Trigger() string {
+	return dj.trigger
+}
+
+// At returns the run time of a one-shot job
+func (dj *DockerJob) At() time.Time {
+	return dj.at
+}
+
+// StartDelay returns how long a start-triggered job waits after the container starts
+func (dj *DockerJob) StartDelay() time.Duration {
+	return dj.startDelay
+}
+
+// StartOnce reports whether a start-triggered job runs only on the container's first start
+func (dj *DockerJob) StartOnce() bool {
+	return dj.startOnce
+}
+
+// MatchesEvent reports whether an event-driven job is triggered by the event
+func (dj *DockerJob) MatchesEvent(event docker.LifecycleEvent) bool {
+	return dj.event != nil && dj.event.pattern.Matches(event)
+}
+
+// QueueEvent debounces a matching event and calls fire once events settle
+func (dj *DockerJob) QueueEvent(event docker.LifecycleEvent, fire func(docker.LifecycleEvent)) {
+	if dj.event != nil {
+		dj.event.queue(event, fire)
+	}
+}
+
+// AllowEventRun applies the job's event rate limit to a run at t
+func (dj *DockerJob) AllowEventRun(t time.Time) bool {
+	return dj.event == nil || dj.event.allow(t)
+}
+
+// Stop cancels anything the job still has pending
+func (dj *DockerJob) Stop() {
+	if dj.event != nil {
+		dj.event.stop()
+	}
+}
+
+// Expression returns the cron expression as written in the label
+func (dj *DockerJob) Expression() string {
 	return dj.cronExpr
 }
 
+// Schedule returns the cron expression with H tokens resolved, prefixed with
+// CRON_TZ= when the job has its own time zone
+func (dj *DockerJob) Schedule() string {
+	return schedule.WithTimezone(dj.resolvedExpr, dj.timezone)
+}
+
+// Describe returns a human-readable description of when the job runs
+func (dj *DockerJob) Describe() string {
+	switch dj.trigger {
+	case types.TriggerAt:
+		return "once at " + dj.at.Format(time.RFC3339)
+	case types.TriggerStart:
+		if dj.startDelay > 0 {
+			return fmt.Sprintf("%s after the container starts", dj.startDelay)
+		}
+		return "when the container starts"
+	case types.TriggerEvent:
+		return "on matching container events"
+	case types.TriggerChain:
+		return "after its upstream jobs"
+	}
+	return schedule.DescribeOrExpr(dj.Schedule(), dj.Key())
+}
+
+// Jitter returns the maximum random delay applied before each run
+func (dj *DockerJob) Jitter() time.Duration {
+	return dj.jitter
+}
+
+// Timezone returns the job's own time zone, empty when it follows the scheduler
+func (dj *DockerJob) Timezone() string {
+	if dj.timezone != "" {
+		return dj.timezone
+	}
+	return schedule.Timezone(dj.cronExpr)
+}
+
+// Suppressed returns why a run at t must be skipped, empty when it may run
+func (dj *DockerJob) Suppressed(t time.Time) string {
+	for _, window := range dj.deny {
+		if window.Contains(t) {
+			return fmt.Sprintf("inside denied window %q", window.String())
+		}
+	}
+
+	if len(dj.allow) == 0 {
+		return ""
+	}
+	for _, window := range dj.allow {
+		if window.Contains(t) {
+			return ""
+		}
+	}
+	return "outside allowed windows"
+}
+
+// RecordSkip remembers the last suppressed run
+func (dj *DockerJob) RecordSkip(at time.Time, reason string) {
+	dj.stateMu.Lock()
+	defer dj.stateMu.Unlock()
+	dj.lastSkip = &at
+	dj.skipReason = reason
+}
+
+func (dj *DockerJob) GetLastSkip() (*time.Time, string) {
+	dj.stateMu.RLock()
+	defer dj.stateMu.RUnlock()
+	return dj.lastSkip, dj.skipReason
+}
+
 func (dj *DockerJob) GetContainerID() string {
 	return dj.containerID
 }
 
+// Service returns the Compose service of the job's container, if any
+func (dj *DockerJob) Service() string {
+	return dj.service
+}
+
+func (dj *DockerJob) GetContainerName() string {
+	return dj.containerName
+}
+
 func (dj *DockerJob) SetCronEntryID(id cron.EntryID) {
 	dj.cronEntryID = id
 }
@@ -78,15 +378,28 @@ func (dj *DockerJob) GetCronEntryID() cron.EntryID {
 	return dj.cronEntryID
 }
 
-func (dj *DockerJob) UpdateNextRun(schedule cron.Schedule) {
-	dj.nextRun = schedule.Next(time.Now())
+func (dj *DockerJob) UpdateNextRun(sched cron.Schedule, location *time.Location) {
+	dj.stateMu.Lock()
+	defer dj.stateMu.Unlock()
+	dj.nextRun = sched.Next(time.Now().In(location))
+}
+
+// SetLastRun restores the last run time remembered from a previous daemon run
+func (dj *DockerJob) SetLastRun(t time.Time) {
+	dj.stateMu.Lock()
+	defer dj.stateMu.Unlock()
+	dj.lastRun = &t
 }
 
 func (dj *DockerJob) GetLastRun() *time.Time {
+	dj.stateMu.RLock()
+	defer dj.stateMu.RUnlock()
 	return dj.lastRun
 }
 
 func (dj *DockerJob) GetNextRun() time.Time {
+	dj.stateMu.RLock()
+	defer dj.stateMu.RUnlock()
 	return dj.nextRun
 }
 
@@ -128,15 +441,15 @@ func (jr *JobRegistry) RemoveJob(jobID string) bool {
 	return false
 }
 
-func (jr *JobRegistry) RemoveJobsByContainer(containerID string) []string {
+func (jr *JobRegistry) RemoveJobsByContainer(containerID string) []*DockerJob {
 	jr.mu.Lock()
 	defer jr.mu.Unlock()
 
-	var removed []string
+	var removed []*DockerJob
 	for id, job := range jr.jobs {
 		if job.containerID == containerID {
 			delete(jr.jobs, id)
-			removed = append(removed, id)
+			removed = append(removed, job)
 		}
 	}
 
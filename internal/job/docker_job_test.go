@@ -0,0 +1,45 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// TestRunStateConcurrency is meant for go test -race: runs write the state
+// the API reads
+func TestRunStateConcurrency(t *testing.T) {
+	dockerJob := testJob("db", "backup", "", "", "")
+	sched := cron.Every(time.Minute)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				dockerJob.SetLastRun(time.Now())
+				dockerJob.RecordSkip(time.Now(), "inside blackout window")
+				dockerJob.UpdateNextRun(sched, time.UTC)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				dockerJob.GetLastRun()
+				dockerJob.GetLastSkip()
+				dockerJob.GetNextRun()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if dockerJob.GetLastRun() == nil || dockerJob.GetNextRun().IsZero() {
+		t.Error("run state not recorded")
+	}
+	if at, reason := dockerJob.GetLastSkip(); at == nil || reason == "" {
+		t.Error("skip not recorded")
+	}
+}
@@ -0,0 +1,78 @@
+// internal/job/event_trigger.go
+package job
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+)
+
+// eventTrigger debounces and rate limits the runs of an event-driven job
+type eventTrigger struct {
+	pattern  *docker.EventPattern
+	debounce time.Duration
+	limit    int
+	per      time.Duration
+
+	mu    sync.Mutex
+	timer *time.Timer
+	runs  []time.Time
+}
+
+// queue waits until matching events have settled for the debounce period
+// and then calls fire with the last of them
+func (et *eventTrigger) queue(event docker.LifecycleEvent, fire func(docker.LifecycleEvent)) {
+	if et.debounce <= 0 {
+		go fire(event)
+		return
+	}
+
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	if et.timer != nil {
+		et.timer.Stop()
+	}
+	et.timer = time.AfterFunc(et.debounce, func() {
+		fire(event)
+	})
+}
+
+// allow reports whether the rate limit leaves room for a run at t and
+// records the run when it does
+func (et *eventTrigger) allow(t time.Time) bool {
+	if et.limit <= 0 {
+		return true
+	}
+
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	// Forget runs that left the window
+	kept := et.runs[:0]
+	for _, run := range et.runs {
+		if t.Sub(run) < et.per {
+			kept = append(kept, run)
+		}
+	}
+	et.runs = kept
+
+	if len(et.runs) >= et.limit {
+		return false
+	}
+
+	et.runs = append(et.runs, t)
+	return true
+}
+
+// stop cancels a pending debounced run
+func (et *eventTrigger) stop() {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	if et.timer != nil {
+		et.timer.Stop()
+		et.timer = nil
+	}
+}
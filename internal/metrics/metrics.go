@@ -0,0 +1,144 @@
+// internal/metrics/metrics.go
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crontask"
+
+// Labels identifying a job on per-job series
+var jobLabels = []string{"job", "container", "service"}
+
+var (
+	// Registry holds every crontask collector plus the Go runtime ones
+	Registry = prometheus.NewRegistry()
+
+	JobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Job runs by outcome (success, failed, skipped, dry_run, interrupted, unknown).",
+	}, append(jobLabels, "outcome"))
+
+	JobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Time a job attempt spent executing.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900, 3600},
+	}, jobLabels)
+
+	JobLateness = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_lateness_seconds",
+		Help:      "Delay between a run's scheduled time and its start.",
+		Buckets:   []float64{0.01, 0.1, 0.5, 1, 5, 15, 60, 300, 900},
+	}, jobLabels)
+
+	RunningExecutions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "running_executions",
+		Help:      "Job executions currently in progress.",
+	}, jobLabels)
+
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Items waiting to be processed, by queue.",
+	}, []string{"queue"})
+
+	RegisteredJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "registered_jobs",
+		Help:      "Jobs currently registered.",
+	})
+
+	RegisteredContainers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "registered_containers",
+		Help:      "Containers with at least one registered job.",
+	})
+
+	DockerAPIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "docker_api_errors_total",
+		Help:      "Failed Docker API calls by operation.",
+	}, []string{"operation"})
+
+	EventStreamReconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "docker_event_stream_reconnects_total",
+		Help:      "Times the Docker event subscription was re-established.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		JobRuns,
+		JobDuration,
+		JobLateness,
+		RunningExecutions,
+		QueueDepth,
+		RegisteredJobs,
+		RegisteredContainers,
+		DockerAPIErrors,
+		EventStreamReconnects,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Job identifies a job on per-job series
+type Job struct {
+	Name      string // Job key, <container name>/<job name>
+	Container string
+	Service   string // Compose service, empty outside Compose
+}
+
+func (j Job) values(extra ...string) []string {
+	return append([]string{j.Name, j.Container, j.Service}, extra...)
+}
+
+// RunStarted counts an execution in progress and how late it started
+func RunStarted(job Job, scheduled time.Time, started time.Time) {
+	RunningExecutions.WithLabelValues(job.values()...).Inc()
+	if !scheduled.IsZero() {
+		JobLateness.WithLabelValues(job.values()...).Observe(max(started.Sub(scheduled), 0).Seconds())
+	}
+}
+
+// RunFinished records the outcome and duration of an execution
+func RunFinished(job Job, outcome string, duration time.Duration) {
+	RunningExecutions.WithLabelValues(job.values()...).Dec()
+	JobDuration.WithLabelValues(job.values()...).Observe(duration.Seconds())
+	JobRuns.WithLabelValues(job.values(outcome)...).Inc()
+}
+
+// RunRecorded counts a run that was settled without executing here
+func RunRecorded(job Job, outcome string) {
+	JobRuns.WithLabelValues(job.values(outcome)...).Inc()
+}
+
+// RemoveJob drops every per-job series of a job that is no longer
+// registered, so removed containers do not leave stale series behind
+func RemoveJob(job Job) {
+	labels := prometheus.Labels{"job": job.Name, "container": job.Container}
+	JobRuns.DeletePartialMatch(labels)
+	JobDuration.DeletePartialMatch(labels)
+	JobLateness.DeletePartialMatch(labels)
+	RunningExecutions.DeletePartialMatch(labels)
+}
+
+// DockerError counts a failed Docker API call
+func DockerError(operation string) {
+	DockerAPIErrors.WithLabelValues(operation).Inc()
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestRunSeries(t *testing.T) {
+	backup := Job{Name: "db/backup", Container: "db", Service: "postgres"}
+	labels := `container="db",job="db/backup",service="postgres"`
+
+	started := time.Now()
+	RunStarted(backup, started.Add(-2*time.Second), started)
+	if body := scrape(t); !strings.Contains(body, "crontask_running_executions{"+labels+"} 1") {
+		t.Errorf("running execution not exposed:\n%s", body)
+	}
+
+	RunFinished(backup, "success", 3*time.Second)
+	RunRecorded(backup, "dry_run")
+
+	body := scrape(t)
+	for _, want := range []string{
+		"crontask_running_executions{" + labels + "} 0",
+		`crontask_job_runs_total{container="db",job="db/backup",outcome="success",service="postgres"} 1`,
+		`crontask_job_runs_total{container="db",job="db/backup",outcome="dry_run",service="postgres"} 1`,
+		"crontask_job_duration_seconds_count{" + labels + "} 1",
+		"crontask_job_duration_seconds_sum{" + labels + "} 3",
+		"crontask_job_lateness_seconds_count{" + labels + "} 1",
+		`crontask_job_lateness_seconds_bucket{` + labels + `,le="5"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+
+	// Removing the job drops every series labelled with it
+	RemoveJob(backup)
+	if body := scrape(t); strings.Contains(body, `job="db/backup"`) {
+		t.Errorf("series left after RemoveJob:\n%s", body)
+	}
+}
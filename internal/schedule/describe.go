@@ -0,0 +1,265 @@
+// internal/schedule/describe.go
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// starBit marks a field written as "*" or "?", mirroring robfig/cron
+const starBit = 1 << 63
+
+var monthNames = []string{"", "January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December"}
+
+// Describe turns a cron expression into a short English description, e.g.
+// "every 15 minutes, Monday through Friday". H tokens are resolved with key,
+// so the description matches the job's actual schedule.
+func Describe(expr string, key string) (string, error) {
+	if err := Validate(expr); err != nil {
+		return "", err
+	}
+
+	resolved, err := ResolveHash(expr, key)
+	if err != nil {
+		return "", err
+	}
+
+	sched, err := Parser.Parse(strings.TrimSpace(resolved))
+	if err != nil {
+		return "", err
+	}
+
+	switch s := sched.(type) {
+	case cron.ConstantDelaySchedule:
+		return "every " + formatDelay(s.Delay), nil
+	case *cron.SpecSchedule:
+		description := describeSpec(s)
+		if tz := Timezone(expr); tz != "" {
+			description += " (" + tz + ")"
+		}
+		return description, nil
+	}
+
+	return strings.TrimSpace(expr), nil
+}
+
+// DescribeOrExpr is Describe, falling back to the expression itself when it
+// cannot be described
+func DescribeOrExpr(expr string, key string) string {
+	description, err := Describe(expr, key)
+	if err != nil {
+		return expr
+	}
+	return description
+}
+
+func describeSpec(s *cron.SpecSchedule) string {
+	times, fixed := describeTime(s)
+	parts := []string{times}
+
+	days := describeDays(s)
+	if days == "" && fixed {
+		parts[0] = "every day " + parts[0]
+	}
+	if days != "" {
+		parts = append(parts, days)
+	}
+	if months := values(s.Month, 1, 12); len(months) < 12 {
+		parts = append(parts, "in "+joinValues(months, monthName))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// describeTime covers the second, minute and hour fields, reporting whether
+// the schedule fires at fixed times of day
+func describeTime(s *cron.SpecSchedule) (string, bool) {
+	seconds := values(s.Second, 0, 59)
+	minutes := values(s.Minute, 0, 59)
+	hours := values(s.Hour, 0, 23)
+
+	// Fixed times of day: "at 09:30" or "at 09:30 and 17:30"
+	if len(seconds) == 1 && len(minutes) == 1 && len(hours) <= 6 {
+		times := make([]string, len(hours))
+		for i, hour := range hours {
+			times[i] = clock(hour, minutes[0], seconds[0])
+		}
+		return "at " + joinList(times), true
+	}
+
+	var parts []string
+	var atMinute string
+	switch {
+	case len(seconds) == 60:
+		parts = append(parts, "every second")
+	case step(seconds, 0, 59) > 0:
+		parts = append(parts, fmt.Sprintf("every %d seconds", step(seconds, 0, 59)))
+	case len(seconds) == 1 && seconds[0] == 0:
+	default:
+		parts = append(parts, "at second "+joinValues(seconds, number))
+	}
+
+	switch {
+	case len(minutes) == 60:
+		if len(seconds) == 1 {
+			parts = append(parts, "every minute")
+		}
+	case step(minutes, 0, 59) > 0:
+		parts = append(parts, fmt.Sprintf("every %d minutes", step(minutes, 0, 59)))
+	case len(minutes) == 1 && len(hours) != 24:
+		if minutes[0] != 0 {
+			atMinute = fmt.Sprintf("at minute %d", minutes[0])
+		}
+	case len(minutes) == 1:
+		if minutes[0] == 0 {
+			parts = append(parts, "every hour")
+		} else {
+			parts = append(parts, fmt.Sprintf("every hour at minute %d", minutes[0]))
+		}
+	default:
+		parts = append(parts, "at minutes "+joinValues(minutes, number))
+	}
+
+	switch {
+	case len(hours) == 24:
+	case step(hours, 0, 23) > 0:
+		parts = append(parts, fmt.Sprintf("every %d hours", step(hours, 0, 23)))
+	case hours[len(hours)-1]-hours[0] == len(hours)-1:
+		parts = append(parts, atMinute)
+		atMinute = ""
+		parts = append(parts, fmt.Sprintf("between %s and %s",
+			clock(hours[0], 0, 0), clock(hours[len(hours)-1], 59, 0)))
+	default:
+		parts = append(parts, atMinute)
+		atMinute = ""
+		parts = append(parts, "in hours "+joinValues(hours, number))
+	}
+
+	if atMinute != "" {
+		parts = append(parts, atMinute)
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " "), false
+}
+
+// describeDays covers day-of-month and day-of-week, which cron combines with
+// "or" when both are restricted
+func describeDays(s *cron.SpecSchedule) string {
+	var dom, dow string
+
+	if days := values(s.Dom, 1, 31); len(days) < 31 {
+		if n := step(days, 1, 31); n > 0 {
+			dom = fmt.Sprintf("every %d days", n)
+		} else {
+			dom = "on day " + joinValues(days, number) + " of the month"
+		}
+	}
+
+	if weekdays := values(s.Dow, 0, 6); len(weekdays) < 7 {
+		dow = joinValues(weekdays, weekdayName)
+		if !strings.Contains(dow, " through ") {
+			dow = "on " + dow
+		}
+	}
+
+	switch {
+	case dom != "" && dow != "" && s.Dom&starBit == 0 && s.Dow&starBit == 0:
+		return dom + " or " + dow
+	case dom != "" && dow != "":
+		return dom + ", " + dow
+	}
+	return dom + dow
+}
+
+// values lists the set bits of a field between min and max
+func values(field uint64, min, max int) []int {
+	var set []int
+	for v := min; v <= max; v++ {
+		if field&(1<<uint(v)) != 0 {
+			set = append(set, v)
+		}
+	}
+	return set
+}
+
+// step returns n when the values are exactly min, min+n, min+2n, ... up to
+// max, 0 otherwise
+func step(set []int, min, max int) int {
+	if len(set) < 2 || set[0] != min {
+		return 0
+	}
+
+	n := set[1] - set[0]
+	if n < 2 || max-set[len(set)-1] >= n {
+		return 0
+	}
+	for i := 2; i < len(set); i++ {
+		if set[i]-set[i-1] != n {
+			return 0
+		}
+	}
+	return n
+}
+
+// joinValues formats the values, folding runs of three or more into ranges
+func joinValues(set []int, name func(int) string) string {
+	var items []string
+	for i := 0; i < len(set); {
+		j := i
+		for j+1 < len(set) && set[j+1] == set[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			items = append(items, name(set[i])+" through "+name(set[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, name(set[k]))
+			}
+		}
+		i = j + 1
+	}
+	return joinList(items)
+}
+
+// joinList joins items as "a, b and c"
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func clock(hour, minute, second int) string {
+	if second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)
+	}
+	return fmt.Sprintf("%02d:%02d", hour, minute)
+}
+
+// formatDelay writes a duration without zero trailing units, 1h30m rather
+// than 1h30m0s
+func formatDelay(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+func number(v int) string {
+	return fmt.Sprint(v)
+}
+
+func monthName(v int) string {
+	return monthNames[v]
+}
+
+func weekdayName(v int) string {
+	return time.Weekday(v).String()
+}
@@ -0,0 +1,68 @@
+package schedule
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"*/15 * * * 1-5", "every 15 minutes, Monday through Friday"},
+		{"30 */2 * * *", "every 2 hours at minute 30"},
+		{"0 0 1 * 1", "at 00:00, on day 1 of the month or on Monday"},
+		{"@daily", "every day at 00:00"},
+		{"@every 90m", "every 1h30m"},
+		{"@every 2h", "every 2h"},
+		{"@every 45s", "every 45s"},
+		{"* * * * *", "every minute"},
+		{"0 * * * *", "every hour"},
+		{"15 * * * *", "every hour at minute 15"},
+		{"5,10,40 * * * *", "at minutes 5, 10 and 40"},
+		{"*/30 * * * * *", "every 30 seconds"},
+		{"30 9,17 * * *", "every day at 09:30 and 17:30"},
+		{"0 9-17 * * Mon-Fri", "between 09:00 and 17:59, Monday through Friday"},
+		{"*/10 9-17 * * *", "every 10 minutes between 09:00 and 17:59"},
+		{"0 0 */2 * *", "at 00:00, every 2 days"},
+		{"0 0 1-3 * *", "at 00:00, on day 1 through 3 of the month"},
+		{"0 0 1 1,7 *", "at 00:00, on day 1 of the month, in January and July"},
+		{"0 0 * * 1,3,5", "at 00:00, on Monday, Wednesday and Friday"},
+		{"0 0 * * 0,6", "at 00:00, on Sunday and Saturday"},
+		{"CRON_TZ=Europe/Berlin 0 6 * * *", "every day at 06:00 (Europe/Berlin)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Describe(tt.expr, "db/backup")
+			if err != nil {
+				t.Fatalf("Describe(%q) = %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Describe(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeHash(t *testing.T) {
+	resolved, err := ResolveHash("H H * * *", "db/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Describe("H H * * *", "db/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := Describe(resolved, "db/backup"); got != want {
+		t.Errorf("Describe(H H * * *) = %q, want the resolved schedule's %q", got, want)
+	}
+}
+
+func TestDescribeOrExpr(t *testing.T) {
+	if got := DescribeOrExpr("61 * * * *", "db/backup"); got != "61 * * * *" {
+		t.Errorf("DescribeOrExpr(invalid) = %q, want the expression", got)
+	}
+	if got := DescribeOrExpr("@hourly", "db/backup"); got != "every hour" {
+		t.Errorf("DescribeOrExpr(@hourly) = %q, want every hour", got)
+	}
+}
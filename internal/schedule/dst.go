@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// allHours is the hour bit set of a schedule that fires every hour
+const allHours = 1<<24 - 1
+
+// dstSchedule adjusts a spec schedule around daylight saving transitions the
+// way classic cron does. A fixed-time run whose wall-clock time is skipped by
+// a spring-forward transition fires right after the transition instead of
+// being lost, and a fixed-time run inside a repeated fall-back hour fires only
+// once. Schedules that fire every hour keep their real-time interval.
+type dstSchedule struct {
+	spec *cron.SpecSchedule
+}
+
+func (s *dstSchedule) Next(t time.Time) time.Time {
+	next := s.spec.Next(t)
+	if next.IsZero() || s.spec.Hour&allHours == allHours {
+		return next
+	}
+
+	loc := s.spec.Location
+	if loc == time.Local {
+		loc = t.Location()
+	}
+
+	if gap, ok := s.skippedRun(t.In(loc), next.In(loc)); ok {
+		return gap.In(t.Location())
+	}
+
+	for isRepeated(next.In(loc)) {
+		next = s.spec.Next(next)
+		if next.IsZero() {
+			break
+		}
+	}
+
+	return next
+}
+
+// skippedRun reports the first spring-forward transition in (from, to] that
+// swallowed a wall-clock time the schedule would have fired at.
+func (s *dstSchedule) skippedRun(from, to time.Time) (time.Time, bool) {
+	cur := from
+	for {
+		_, end := cur.ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			return time.Time{}, false
+		}
+
+		_, before := cur.Zone()
+		_, after := end.Zone()
+		if after > before {
+			// Evaluate the schedule as if the old offset had continued
+			shifted := *s.spec
+			shifted.Location = time.FixedZone("", before)
+
+			gap := time.Duration(after-before) * time.Second
+			if run := shifted.Next(end.Add(-time.Second)); run.Before(end.Add(gap)) {
+				return end, true
+			}
+		}
+
+		cur = end
+	}
+}
+
+// isRepeated reports whether the wall-clock time of t already occurred
+// earlier because of a fall-back transition.
+func isRepeated(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, previous := start.Add(-time.Second).Zone()
+	if previous <= offset {
+		return false
+	}
+
+	return t.Sub(start) < time.Duration(previous-offset)*time.Second
+}
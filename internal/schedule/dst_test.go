@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDSTSchedule(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// 2026-03-08 02:00 EST jumps to 03:00 EDT, 2026-11-01 02:00 EDT falls
+	// back to 01:00 EST
+	at := func(day int, month time.Month, hour, minute int, offset int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.FixedZone("", offset*3600)).In(newYork)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		to   time.Time
+		want []time.Time
+	}{
+		{
+			name: "fixed time in the skipped hour runs once after the jump",
+			expr: "30 2 * * *",
+			from: at(7, time.March, 12, 0, -5),
+			to:   at(9, time.March, 12, 0, -4),
+			want: []time.Time{
+				at(8, time.March, 3, 0, -4),
+				at(9, time.March, 2, 30, -4),
+			},
+		},
+		{
+			name: "interval in the skipped hour runs once after the jump",
+			expr: "*/20 2 * * *",
+			from: at(8, time.March, 0, 0, -5),
+			to:   at(8, time.March, 12, 0, -4),
+			want: []time.Time{
+				at(8, time.March, 3, 0, -4),
+			},
+		},
+		{
+			name: "fixed time outside the skipped hour is unaffected",
+			expr: "0 4 * * *",
+			from: at(7, time.March, 12, 0, -5),
+			to:   at(8, time.March, 12, 0, -4),
+			want: []time.Time{
+				at(8, time.March, 4, 0, -4),
+			},
+		},
+		{
+			name: "fixed time in the repeated hour runs once",
+			expr: "30 1 * * *",
+			from: at(31, time.October, 12, 0, -4),
+			to:   at(1, time.November, 12, 0, -5),
+			want: []time.Time{
+				at(1, time.November, 1, 30, -4),
+			},
+		},
+		{
+			name: "interval in the repeated hour runs once per wall-clock time",
+			expr: "*/20 1 * * *",
+			from: at(1, time.November, 0, 0, -4),
+			to:   at(1, time.November, 12, 0, -5),
+			want: []time.Time{
+				at(1, time.November, 1, 0, -4),
+				at(1, time.November, 1, 20, -4),
+				at(1, time.November, 1, 40, -4),
+			},
+		},
+		{
+			name: "hourly keeps its real-time interval across spring-forward",
+			expr: "0 * * * *",
+			from: at(8, time.March, 0, 30, -5),
+			to:   at(8, time.March, 4, 30, -4),
+			want: []time.Time{
+				at(8, time.March, 1, 0, -5),
+				at(8, time.March, 3, 0, -4),
+				at(8, time.March, 4, 0, -4),
+			},
+		},
+		{
+			name: "hourly keeps its real-time interval across fall-back",
+			expr: "0 * * * *",
+			from: at(1, time.November, 0, 30, -4),
+			to:   at(1, time.November, 2, 30, -5),
+			want: []time.Time{
+				at(1, time.November, 1, 0, -4),
+				at(1, time.November, 1, 0, -5),
+				at(1, time.November, 2, 0, -5),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := Parse("CRON_TZ=America/New_York " + tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []time.Time
+			for next := sched.Next(tt.from); !next.After(tt.to); next = sched.Next(next) {
+				got = append(got, next)
+				if len(got) > len(tt.want) {
+					break
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got runs %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("run %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
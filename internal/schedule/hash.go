@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Range the H token may resolve to for each field. Days of the month stop at
+// 28 so a hashed day exists in every month.
+var hashBounds = map[string][2]int{
+	"second":       {0, 59},
+	"minute":       {0, 59},
+	"hour":         {0, 23},
+	"day-of-month": {1, 28},
+	"month":        {1, 12},
+	"day-of-week":  {0, 6},
+}
+
+// ResolveHash replaces Jenkins-style H tokens with values derived from key, so
+// jobs sharing an expression spread out while each job keeps the same time
+// across restarts. Supported forms are H, H/step, H(lo-hi) and H(lo-hi)/step.
+func ResolveHash(expr string, key string) (string, error) {
+	spec := strings.TrimSpace(expr)
+
+	var prefix string
+	if Timezone(spec) != "" {
+		i := strings.IndexAny(spec, " \t")
+		prefix, spec = spec[:i+1], strings.TrimSpace(spec[i:])
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		return expr, nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 && len(fields) != 6 {
+		return expr, nil
+	}
+
+	names := fieldNames
+	if len(fields) == 5 {
+		names = fieldNames[1:]
+	}
+
+	for i, field := range fields {
+		parts := strings.Split(field, ",")
+		for j, part := range parts {
+			resolved, err := resolveHashPart(part, names[i], key)
+			if err != nil {
+				return "", &FieldError{Field: names[i], Value: field, Err: err}
+			}
+			parts[j] = resolved
+		}
+		fields[i] = strings.Join(parts, ",")
+	}
+
+	return prefix + strings.Join(fields, " "), nil
+}
+
+// Resolve a single comma-separated part of a field
+func resolveHashPart(part string, field string, key string) (string, error) {
+	if !strings.HasPrefix(part, "H") {
+		return part, nil
+	}
+
+	bounds := hashBounds[field]
+	lo, hi := bounds[0], bounds[1]
+	rest := part[1:]
+
+	// Optional (lo-hi) range
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end == -1 {
+			return "", fmt.Errorf("unterminated H range")
+		}
+
+		from, to, found := strings.Cut(rest[1:end], "-")
+		if !found {
+			return "", fmt.Errorf("H range must look like H(lo-hi)")
+		}
+
+		var err error
+		if lo, err = strconv.Atoi(from); err != nil {
+			return "", fmt.Errorf("invalid H range start %q", from)
+		}
+		if hi, err = strconv.Atoi(to); err != nil {
+			return "", fmt.Errorf("invalid H range end %q", to)
+		}
+		if lo < bounds[0] || hi > bounds[1] || lo > hi {
+			return "", fmt.Errorf("H range %d-%d outside %d-%d", lo, hi, bounds[0], bounds[1])
+		}
+
+		rest = rest[end+1:]
+	}
+
+	// Optional /step
+	step := 0
+	if rest != "" {
+		if !strings.HasPrefix(rest, "/") {
+			return "", fmt.Errorf("unexpected %q after H", rest)
+		}
+
+		var err error
+		if step, err = strconv.Atoi(rest[1:]); err != nil || step <= 0 {
+			return "", fmt.Errorf("invalid H step %q", rest[1:])
+		}
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key + "|" + field))
+	sum := h.Sum64()
+
+	span := hi - lo + 1
+	if step == 0 {
+		return strconv.Itoa(lo + int(sum%uint64(span))), nil
+	}
+
+	if step > span {
+		step = span
+	}
+	start := lo + int(sum%uint64(step))
+	return fmt.Sprintf("%d-%d/%d", start, hi, step), nil
+}
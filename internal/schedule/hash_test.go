@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestResolveHashStable(t *testing.T) {
+	tests := []string{
+		"H * * * *",
+		"H H * * *",
+		"H */2 * * *",
+		"H/15 * * * *",
+		"H(0-29) H(1-5) * * *",
+		"H H H * * *",
+		"CRON_TZ=UTC H 3 * * *",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			first, err := ResolveHash(expr, "db/backup")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(strings.TrimPrefix(first, "CRON_TZ=UTC"), "H") {
+				t.Fatalf("ResolveHash(%q) = %q, H left unresolved", expr, first)
+			}
+			if err := Validate(first); err != nil {
+				t.Fatalf("ResolveHash(%q) = %q, invalid: %v", expr, first, err)
+			}
+
+			for i := 0; i < 10; i++ {
+				again, err := ResolveHash(expr, "db/backup")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if again != first {
+					t.Fatalf("ResolveHash(%q) = %q, then %q", expr, first, again)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveHashUnchanged(t *testing.T) {
+	for _, expr := range []string{"0 * * * *", "@hourly", "@every 90s", "* * *"} {
+		resolved, err := ResolveHash(expr, "db/backup")
+		if err != nil {
+			t.Fatalf("ResolveHash(%q) = %v", expr, err)
+		}
+		if resolved != expr {
+			t.Errorf("ResolveHash(%q) = %q, want it unchanged", expr, resolved)
+		}
+	}
+}
+
+func TestResolveHashSpread(t *testing.T) {
+	tests := []struct {
+		expr   string
+		lo, hi int
+		step   bool
+	}{
+		{expr: "H * * * *", lo: 0, hi: 59},
+		{expr: "H(10-19) * * * *", lo: 10, hi: 19},
+		{expr: "H/15 * * * *", lo: 0, hi: 14, step: true},
+	}
+
+	const jobs = 600
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			counts := make(map[int]int)
+			for i := 0; i < jobs; i++ {
+				resolved, err := ResolveHash(tt.expr, fmt.Sprintf("container-%d/job", i))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				minute := strings.Fields(resolved)[0]
+				if tt.step {
+					start, rest, _ := strings.Cut(minute, "-")
+					if rest != "59/15" {
+						t.Fatalf("ResolveHash(%q) minute = %q, want <start>-59/15", tt.expr, minute)
+					}
+					minute = start
+				}
+
+				value, err := strconv.Atoi(minute)
+				if err != nil {
+					t.Fatalf("ResolveHash(%q) minute = %q: %v", tt.expr, minute, err)
+				}
+				if value < tt.lo || value > tt.hi {
+					t.Fatalf("ResolveHash(%q) minute = %d, outside %d-%d", tt.expr, value, tt.lo, tt.hi)
+				}
+				counts[value]++
+			}
+
+			// Every value is used and none takes more than twice its share
+			span := tt.hi - tt.lo + 1
+			if len(counts) != span {
+				t.Errorf("%d of %d values used", len(counts), span)
+			}
+			for value, n := range counts {
+				if n > 2*jobs/span {
+					t.Errorf("value %d used by %d of %d jobs", value, n, jobs)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveHashErrors(t *testing.T) {
+	for _, expr := range []string{
+		"H(5) * * * *",
+		"H(0-60) * * * *",
+		"H(9-3) * * * *",
+		"H(1-5 * * * *",
+		"H/0 * * * *",
+		"Hx * * * *",
+		"0 0 H(1-31) * *",
+	} {
+		if _, err := ResolveHash(expr, "db/backup"); err == nil {
+			t.Errorf("ResolveHash(%q) = nil error, want one", expr)
+		}
+	}
+}
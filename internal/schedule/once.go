@@ -0,0 +1,24 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// onceSchedule fires a single time
+type onceSchedule struct {
+	at time.Time
+}
+
+// Once returns a schedule that fires at the given time and never again
+func Once(at time.Time) cron.Schedule {
+	return &onceSchedule{at: at}
+}
+
+func (s *onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at.In(t.Location())
+	}
+	return time.Time{}
+}
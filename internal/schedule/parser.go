@@ -0,0 +1,121 @@
+// internal/schedule/parser.go
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Parser is the cron parser used by the worker scheduler. Label validation
+// must go through the same parser so that anything accepted on a container
+// is also accepted by the scheduler.
+var Parser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// Field names in the order they appear in a six-field expression
+var fieldNames = []string{"second", "minute", "hour", "day-of-month", "month", "day-of-week"}
+
+// FieldError reports which part of a cron expression is invalid
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid %s field %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Parse validates the expression and returns its schedule, adjusted for
+// daylight saving transitions in the schedule's time zone.
+func Parse(expr string) (cron.Schedule, error) {
+	if err := Validate(expr); err != nil {
+		return nil, err
+	}
+
+	sched, err := Parser.Parse(strings.TrimSpace(expr))
+	if err != nil {
+		return nil, err
+	}
+
+	if spec, ok := sched.(*cron.SpecSchedule); ok {
+		return &dstSchedule{spec: spec}, nil
+	}
+	return sched, nil
+}
+
+// Validate checks a cron expression with the scheduler's parser and, when it
+// is rejected, reports the offending field.
+func Validate(expr string) error {
+	spec := strings.TrimSpace(expr)
+	if spec == "" {
+		return fmt.Errorf("empty cron expression")
+	}
+
+	// Optional CRON_TZ=/TZ= prefix
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i == -1 {
+			return fmt.Errorf("missing schedule after time zone prefix %q", spec)
+		}
+		zone := spec[strings.Index(spec, "=")+1 : i]
+		if _, err := time.LoadLocation(zone); err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", zone, err)
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+
+	// Descriptors: @daily, @every 90s, ...
+	if strings.HasPrefix(spec, "@") {
+		if _, err := Parser.Parse(spec); err != nil {
+			return fmt.Errorf("invalid descriptor %q: %w", spec, err)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 && len(fields) != 6 {
+		return fmt.Errorf("expected 5 or 6 fields, got %d in %q", len(fields), spec)
+	}
+
+	// H tokens resolve per job, any key will do to check the rest of the field
+	resolved, err := ResolveHash(spec, "")
+	if err != nil {
+		return err
+	}
+	spec = resolved
+	fields = strings.Fields(spec)
+
+	_, err = Parser.Parse(spec)
+	if err == nil {
+		return nil
+	}
+
+	names := fieldNames
+	if len(fields) == 5 {
+		names = fieldNames[1:]
+	}
+
+	// Probe each field on its own to find the one the parser rejects
+	for i, value := range fields {
+		probe := make([]string, len(fields))
+		for j := range probe {
+			probe[j] = "*"
+		}
+		probe[i] = value
+
+		if _, probeErr := Parser.Parse(strings.Join(probe, " ")); probeErr != nil {
+			return &FieldError{Field: names[i], Value: value, Err: probeErr}
+		}
+	}
+
+	return err
+}
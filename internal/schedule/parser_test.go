@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		expr  string
+		valid bool
+		field string // Field a FieldError must name, if any
+	}{
+		{expr: "*/5 * * * *", valid: true},
+		{expr: "0 30 9 * * Mon-Fri", valid: true},
+		{expr: "@daily", valid: true},
+		{expr: "@every 90s", valid: true},
+		{expr: "CRON_TZ=Europe/Berlin 0 6 * * *", valid: true},
+		{expr: "TZ=UTC @hourly", valid: true},
+		{expr: "H H * * *", valid: true},
+		{expr: ""},
+		{expr: "* * * *"},
+		{expr: "* * * * * * *"},
+		{expr: "@fortnightly"},
+		{expr: "@every soon"},
+		{expr: "CRON_TZ=Europe/Berlin"},
+		{expr: "CRON_TZ=Mars/Olympus 0 6 * * *"},
+		{expr: "61 * * * *", field: "minute"},
+		{expr: "0 25 * * *", field: "hour"},
+		{expr: "0 0 32 * *", field: "day-of-month"},
+		{expr: "0 0 * 13 *", field: "month"},
+		{expr: "0 0 * * Funday", field: "day-of-week"},
+		{expr: "60 0 0 * * *", field: "second"},
+		{expr: "H(50-70) * * * *", field: "minute"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			err := Validate(tt.expr)
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("Validate(%q) = %v, want nil", tt.expr, err)
+				}
+				resolved, err := ResolveHash(tt.expr, "job")
+				if err != nil {
+					t.Fatalf("ResolveHash(%q) = %v, want nil", tt.expr, err)
+				}
+				if _, err := Parse(resolved); err != nil {
+					t.Fatalf("Parse(%q) = %v, want nil", resolved, err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Validate(%q) = nil, want an error", tt.expr)
+			}
+			if tt.field == "" {
+				return
+			}
+
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("Validate(%q) = %v, want a FieldError", tt.expr, err)
+			}
+			if fieldErr.Field != tt.field {
+				t.Errorf("Validate(%q) field = %q, want %q", tt.expr, fieldErr.Field, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoadLocation resolves a configured time zone name. An empty name and
+// "Local" both mean the process-local zone.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// Timezone returns the zone named by a CRON_TZ=/TZ= prefix, if any
+func Timezone(expr string) string {
+	spec := strings.TrimSpace(expr)
+	if !strings.HasPrefix(spec, "TZ=") && !strings.HasPrefix(spec, "CRON_TZ=") {
+		return ""
+	}
+
+	i := strings.IndexAny(spec, " \t")
+	if i == -1 {
+		return ""
+	}
+	return spec[strings.Index(spec, "=")+1 : i]
+}
+
+// WithTimezone prefixes the expression with CRON_TZ= unless it already
+// carries its own zone or no zone is given.
+func WithTimezone(expr string, tz string) string {
+	if tz == "" || Timezone(expr) != "" {
+		return expr
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", tz, strings.TrimSpace(expr))
+}
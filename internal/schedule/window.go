@@ -0,0 +1,212 @@
+// internal/schedule/window.go
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a period of time in which runs are allowed or suppressed. It is
+// either an absolute range ("2026-01-02T22:00:00Z/2026-01-03T02:00:00Z") or a
+// recurring one made of weekdays and/or a time of day ("Mon-Fri 01:00-05:00",
+// "Sat,Sun", "22:00-02:00"). Recurring windows are evaluated in the zone of
+// the time passed to Contains.
+type Window struct {
+	raw   string
+	start time.Time
+	end   time.Time
+	days  uint8
+	from  int
+	to    int
+	daily bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+const allDays = 1<<7 - 1
+
+// ParseWindows parses a ";"-separated list of windows
+func ParseWindows(spec string) ([]*Window, error) {
+	var windows []*Window
+	for _, part := range strings.Split(spec, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		window, err := ParseWindow(part)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+// ParseWindow parses a single window
+func ParseWindow(spec string) (*Window, error) {
+	spec = strings.TrimSpace(spec)
+	w := &Window{raw: spec}
+
+	// Absolute range
+	if from, to, found := strings.Cut(spec, "/"); found {
+		var err error
+		if w.start, err = time.Parse(time.RFC3339, strings.TrimSpace(from)); err != nil {
+			return nil, fmt.Errorf("invalid window %q: bad start: %w", spec, err)
+		}
+		if w.end, err = time.Parse(time.RFC3339, strings.TrimSpace(to)); err != nil {
+			return nil, fmt.Errorf("invalid window %q: bad end: %w", spec, err)
+		}
+		if !w.end.After(w.start) {
+			return nil, fmt.Errorf("invalid window %q: end is not after start", spec)
+		}
+		return w, nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid window %q: expected [days] [HH:MM-HH:MM]", spec)
+	}
+
+	w.daily = true
+	for _, field := range fields {
+		var err error
+		if strings.Contains(field, ":") {
+			if !w.daily {
+				return nil, fmt.Errorf("invalid window %q: more than one time range", spec)
+			}
+			w.from, w.to, err = parseTimeRange(field)
+			w.daily = false
+		} else {
+			if w.days != 0 {
+				return nil, fmt.Errorf("invalid window %q: more than one day list", spec)
+			}
+			w.days, err = parseDays(field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+		}
+	}
+
+	if w.days == 0 {
+		w.days = allDays
+	}
+
+	return w, nil
+}
+
+// Contains reports whether t falls inside the window
+func (w *Window) Contains(t time.Time) bool {
+	if !w.start.IsZero() {
+		return !t.Before(w.start) && t.Before(w.end)
+	}
+
+	if w.daily {
+		return w.hasDay(t.Weekday())
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if w.from < w.to {
+		return w.hasDay(t.Weekday()) && minute >= w.from && minute < w.to
+	}
+
+	// The range crosses midnight, the early part belongs to the previous day
+	if minute >= w.from {
+		return w.hasDay(t.Weekday())
+	}
+	if minute < w.to {
+		return w.hasDay((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+func (w *Window) String() string {
+	return w.raw
+}
+
+func (w *Window) hasDay(day time.Weekday) bool {
+	return w.days&(1<<uint(day)) != 0
+}
+
+// Parse "Mon-Fri", "Sat,Sun" or "Fri-Mon" into a weekday bit set
+func parseDays(spec string) (uint8, error) {
+	var days uint8
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+
+		first, ok := weekdays[strings.ToLower(from)]
+		if !ok {
+			return 0, fmt.Errorf("unknown weekday %q", from)
+		}
+
+		last := first
+		if isRange {
+			if last, ok = weekdays[strings.ToLower(to)]; !ok {
+				return 0, fmt.Errorf("unknown weekday %q", to)
+			}
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			days |= 1 << uint(day)
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// Parse "HH:MM-HH:MM" into minutes of the day
+func parseTimeRange(spec string) (int, int, error) {
+	from, to, found := strings.Cut(spec, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("time range %q must look like HH:MM-HH:MM", spec)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("time range %q is empty", spec)
+	}
+
+	return start, end, nil
+}
+
+// Parse "HH:MM" into minutes of the day, "24:00" is accepted as an end
+func parseClock(spec string) (int, error) {
+	hour, minute, found := strings.Cut(spec, ":")
+	if !found {
+		return 0, fmt.Errorf("invalid time %q", spec)
+	}
+
+	h, err := strconv.Atoi(hour)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", spec)
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q", spec)
+	}
+	if h < 0 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", spec)
+	}
+
+	return h*60 + m, nil
+}
@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowContains(t *testing.T) {
+	// 2026-01-05 is a Monday
+	at := func(day int, hour, minute int) time.Time {
+		return time.Date(2026, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"01:00-05:00", at(5, 0, 59), false},
+		{"01:00-05:00", at(5, 1, 0), true},
+		{"01:00-05:00", at(5, 4, 59), true},
+		{"01:00-05:00", at(5, 5, 0), false},
+		{"Mon-Fri 01:00-05:00", at(5, 2, 0), true},
+		{"Mon-Fri 01:00-05:00", at(10, 2, 0), false},
+		{"Sat,Sun", at(10, 12, 0), true},
+		{"Sat,Sun", at(11, 23, 59), true},
+		{"Sat,Sun", at(12, 0, 0), false},
+		{"Fri-Mon", at(5, 12, 0), true},
+		{"Fri-Mon", at(6, 12, 0), false},
+		{"20:00-24:00", at(5, 23, 59), true},
+		{"20:00-24:00", at(6, 0, 0), false},
+
+		// Overnight ranges belong to the day they start on
+		{"22:00-02:00", at(5, 21, 59), false},
+		{"22:00-02:00", at(5, 22, 0), true},
+		{"22:00-02:00", at(6, 0, 0), true},
+		{"22:00-02:00", at(6, 1, 59), true},
+		{"22:00-02:00", at(6, 2, 0), false},
+		{"Fri 22:00-02:00", at(9, 23, 0), true},
+		{"Fri 22:00-02:00", at(10, 1, 0), true},
+		{"Fri 22:00-02:00", at(9, 1, 0), false},
+		{"Fri 22:00-02:00", at(10, 23, 0), false},
+		{"Sun 23:00-01:00", at(12, 0, 30), true},
+
+		// Absolute ranges include the start and exclude the end
+		{"2026-01-05T22:00:00Z/2026-01-06T02:00:00Z", at(5, 21, 59), false},
+		{"2026-01-05T22:00:00Z/2026-01-06T02:00:00Z", at(5, 22, 0), true},
+		{"2026-01-05T22:00:00Z/2026-01-06T02:00:00Z", at(6, 1, 59), true},
+		{"2026-01-05T22:00:00Z/2026-01-06T02:00:00Z", at(6, 2, 0), false},
+		{"2026-01-05T22:00:00+01:00/2026-01-06T02:00:00+01:00", at(5, 21, 0), true},
+	}
+
+	for _, tt := range tests {
+		window, err := ParseWindow(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseWindow(%q) = %v", tt.spec, err)
+		}
+		if got := window.Contains(tt.t); got != tt.want {
+			t.Errorf("%q.Contains(%s %s) = %v, want %v",
+				tt.spec, tt.t.Weekday(), tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestWindowContainsZone(t *testing.T) {
+	tehran, err := time.LoadLocation("Asia/Tehran")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	window, err := ParseWindow("01:00-05:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:00 UTC is 02:30 in Tehran
+	ts := time.Date(2026, time.January, 5, 23, 0, 0, 0, time.UTC)
+	if window.Contains(ts) {
+		t.Errorf("Contains(%s) = true in UTC, want false", ts)
+	}
+	if !window.Contains(ts.In(tehran)) {
+		t.Errorf("Contains(%s) = false, want true", ts.In(tehran))
+	}
+}
+
+func TestParseWindowErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"Mon-Fri 01:00-05:00 extra",
+		"Funday",
+		"Mon-Funday",
+		"Mon Tue",
+		"01:00-05:00 02:00-03:00",
+		"01:00",
+		"01:00-01:00",
+		"25:00-26:00",
+		"01:60-02:00",
+		"24:30-01:00",
+		"2026-01-05T22:00:00Z/yesterday",
+		"2026-01-06T02:00:00Z/2026-01-05T22:00:00Z",
+	} {
+		if _, err := ParseWindow(spec); err == nil {
+			t.Errorf("ParseWindow(%q) = nil error, want one", spec)
+		}
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	windows, err := ParseWindows("Sat,Sun; 22:00-02:00 ;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 2 || windows[0].String() != "Sat,Sun" || windows[1].String() != "22:00-02:00" {
+		t.Errorf("ParseWindows = %v, want [Sat,Sun 22:00-02:00]", windows)
+	}
+
+	if _, err := ParseWindows("Sat,Sun;Funday"); err == nil {
+		t.Error("ParseWindows with an invalid window = nil error, want one")
+	}
+}
@@ -0,0 +1,399 @@
+// internal/store/store.go
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Run outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+	OutcomeSkipped = "skipped"
+	OutcomeDryRun  = "dry_run" // Would have run, dry-run mode executes nothing
+
+	// Outcomes of runs left unfinished by a daemon restart
+	OutcomeInterrupted = "interrupted"
+	OutcomeUnknown     = "unknown"
+)
+
+var (
+	runsBucket = []byte("runs")
+	jobsBucket = []byte("jobs")
+
+	// Runs that started but have no outcome yet
+	runningBucket = []byte("running")
+
+	// Bookkeeping, e.g. the number of runs so pruning does not count them
+	metaBucket  = []byte("meta")
+	runCountKey = []byte("run_count")
+)
+
+// ErrNoStore is returned by history queries when no store is configured
+var ErrNoStore = errors.New("history store is not enabled")
+
+// RunRecord is one run of a job
+type RunRecord struct {
+	RunID         string    `json:"run_id"`
+	JobID         string    `json:"job_id"`
+	JobKey        string    `json:"job_key"`
+	JobName       string    `json:"job_name"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	Service       string    `json:"service,omitempty"`
+	Trigger       string    `json:"trigger"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+	ExitCode      int       `json:"exit_code"`
+	Output        string    `json:"output,omitempty"`
+	Truncated     bool      `json:"truncated,omitempty"`
+	Attempt       int       `json:"attempt"`
+	ExecID        string    `json:"exec_id,omitempty"`
+	CatchUp       bool      `json:"catch_up,omitempty"`
+	Manual        bool      `json:"manual,omitempty"`
+	Outcome       string    `json:"outcome"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+// JobState is what is remembered about a job between runs, keyed by the
+// job's container/name key so it survives container re-creation
+type JobState struct {
+	Key           string    `json:"key"`
+	LastRun       time.Time `json:"last_run"`
+	LastOutcome   string    `json:"last_outcome"`
+	LastScheduled time.Time `json:"last_scheduled"` // Latest scheduled time handled, skipped runs included
+	Paused        bool      `json:"paused,omitempty"`
+}
+
+// Query filters run history, zero fields match everything
+type Query struct {
+	JobKey    string
+	JobName   string
+	Container string // Container name or ID prefix
+	Since     time.Time
+	Until     time.Time
+	Limit     int // Newest records first
+}
+
+// Store keeps job state and run history in a single bbolt file
+type Store struct {
+	db     *bolt.DB
+	config *types.StoreConfig
+}
+
+// Open opens or creates the store file
+func Open(config *types.StoreConfig) (*Store, error) {
+	path := config.Path
+	if path == "" {
+		path = getDefaultStorePath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{runsBucket, jobsBucket, runningBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+
+		// Stores written before the count was kept are counted once
+		if tx.Bucket(metaBucket).Get(runCountKey) == nil {
+			count := 0
+			cursor := tx.Bucket(runsBucket).Cursor()
+			for key, _ := cursor.First(); key != nil; key, _ = cursor.Next() {
+				count++
+			}
+			return setRunCount(tx, count)
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return &Store{db: db, config: config}, nil
+}
+
+// Close closes the store file
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the store file location
+func (s *Store) Path() string {
+	return s.db.Path()
+}
+
+// RecordRun saves a run, updates the job's state and applies retention
+func (s *Store) RecordRun(record RunRecord) error {
+	if limit := s.config.OutputLimit; limit > 0 && len(record.Output) > limit {
+		// Cut at a rune boundary so the output stays valid UTF-8
+		for limit > 0 && !utf8.RuneStart(record.Output[limit]) {
+			limit--
+		}
+		record.Output = record.Output[:limit]
+		record.Truncated = true
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(runsBucket)
+		key := runKey(record)
+		if runs.Get(key) == nil {
+			if err := setRunCount(tx, runCount(tx)+1); err != nil {
+				return err
+			}
+		}
+		if err := runs.Put(key, data); err != nil {
+			return err
+		}
+
+		if err := tx.Bucket(runningBucket).Delete(runningKey(record)); err != nil {
+			return err
+		}
+
+		if err := s.updateJobState(tx, record); err != nil {
+			return err
+		}
+
+		return s.prune(tx)
+	})
+}
+
+// BeginRun remembers a started run until RecordRun saves its outcome, so
+// runs cut short by a restart can be found again
+func (s *Store) BeginRun(record RunRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(runningBucket).Put(runningKey(record), data)
+	})
+}
+
+// Unfinished returns the runs that started but never recorded an outcome
+func (s *Store) Unfinished() ([]RunRecord, error) {
+	var records []RunRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(runningBucket).ForEach(func(_, data []byte) error {
+			var record RunRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	return records, err
+}
+
+// updateJobState folds a run into the job's saved state
+func (s *Store) updateJobState(tx *bolt.Tx, record RunRecord) error {
+	jobs := tx.Bucket(jobsBucket)
+
+	state := JobState{Key: record.JobKey}
+	if data := jobs.Get([]byte(record.JobKey)); data != nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return err
+		}
+	}
+
+	// Only real executions count as a job's last run
+	realRun := record.Outcome != OutcomeSkipped && record.Outcome != OutcomeDryRun
+	if realRun && !record.StartedAt.Before(state.LastRun) {
+		state.LastRun = record.StartedAt
+		state.LastOutcome = record.Outcome
+	}
+	if record.ScheduledAt.After(state.LastScheduled) {
+		state.LastScheduled = record.ScheduledAt
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return jobs.Put([]byte(record.JobKey), data)
+}
+
+// SetPaused remembers whether a job is paused
+func (s *Store) SetPaused(key string, paused bool) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		jobs := tx.Bucket(jobsBucket)
+
+		state := JobState{Key: key}
+		if data := jobs.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, &state); err != nil {
+				return err
+			}
+		}
+		state.Paused = paused
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		return jobs.Put([]byte(key), data)
+	})
+}
+
+// JobState returns the remembered state of a job
+func (s *Store) JobState(key string) (*JobState, error) {
+	var state *JobState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		state = &JobState{}
+		return json.Unmarshal(data, state)
+	})
+	return state, err
+}
+
+// History returns the runs matching the query, newest first
+func (s *Store) History(query Query) ([]RunRecord, error) {
+	var records []RunRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(runsBucket).Cursor()
+		for key, data := cursor.Last(); key != nil; key, data = cursor.Prev() {
+			var record RunRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+
+			if !query.Until.IsZero() && !record.StartedAt.Before(query.Until) {
+				continue
+			}
+			if !query.Since.IsZero() && record.StartedAt.Before(query.Since) {
+				break
+			}
+			if !query.matches(record) {
+				continue
+			}
+
+			records = append(records, record)
+			if query.Limit > 0 && len(records) >= query.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	return records, err
+}
+
+func (q Query) matches(record RunRecord) bool {
+	if q.JobKey != "" && record.JobKey != q.JobKey && record.JobID != q.JobKey {
+		return false
+	}
+	if q.JobName != "" && record.JobName != q.JobName {
+		return false
+	}
+	if q.Container != "" && record.ContainerName != q.Container && !strings.HasPrefix(record.ContainerID, q.Container) {
+		return false
+	}
+	return true
+}
+
+// prune removes the oldest runs beyond the configured count and age
+func (s *Store) prune(tx *bolt.Tx) error {
+	cursor := tx.Bucket(runsBucket).Cursor()
+	count := runCount(tx)
+	pruned := 0
+
+	var cutoff string
+	if s.config.MaxAge > 0 {
+		cutoff = runKeyPrefix(time.Now().Add(-s.config.MaxAge))
+	}
+
+	// Keys sort oldest first, stop at the first run that is kept
+	for key, _ := cursor.First(); key != nil; key, _ = cursor.First() {
+		tooOld := cutoff != "" && string(key) < cutoff
+		tooMany := s.config.MaxRuns > 0 && count-pruned > s.config.MaxRuns
+		if !tooOld && !tooMany {
+			break
+		}
+
+		if err := cursor.Delete(); err != nil {
+			return err
+		}
+		pruned++
+	}
+
+	if pruned == 0 {
+		return nil
+	}
+	return setRunCount(tx, count-pruned)
+}
+
+// runCount returns the number of runs in the store
+func runCount(tx *bolt.Tx) int {
+	data := tx.Bucket(metaBucket).Get(runCountKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(data))
+}
+
+func setRunCount(tx *bolt.Tx, count int) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(max(count, 0)))
+	return tx.Bucket(metaBucket).Put(runCountKey, data)
+}
+
+// Keys sort by start time so cursors walk the history in order
+func runKey(record RunRecord) []byte {
+	return []byte(fmt.Sprintf("%s-%s-%s", runKeyPrefix(record.StartedAt), record.RunID, record.JobID))
+}
+
+// An attempt is running until its outcome is recorded
+func runningKey(record RunRecord) []byte {
+	return []byte(fmt.Sprintf("%s-%s-%d", record.RunID, record.JobID, record.Attempt))
+}
+
+func runKeyPrefix(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
+
+// getDefaultStorePath returns the default store file path based on OS
+func getDefaultStorePath() string {
+	switch runtime.GOOS {
+	case "windows":
+		programData := os.Getenv("PROGRAMDATA")
+		if programData == "" {
+			programData = "C:\\ProgramData"
+		}
+		return filepath.Join(programData, "crontask", "crontask.db")
+	case "darwin":
+		return "/Library/Application Support/crontask/crontask.db"
+	default: // linux and other unix-like
+		return "/var/lib/crontask/crontask.db"
+	}
+}
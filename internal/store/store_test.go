@@ -0,0 +1,265 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+func openTestStore(t *testing.T, config types.StoreConfig) *Store {
+	t.Helper()
+	if config.Path == "" {
+		config.Path = filepath.Join(t.TempDir(), "crontask.db")
+	}
+
+	s, err := Open(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func testRecord(job string, container string, started time.Time) RunRecord {
+	return RunRecord{
+		RunID:         fmt.Sprintf("run-%d", started.UnixNano()),
+		JobID:         "docker-" + container + "-" + job,
+		JobKey:        container + "/" + job,
+		JobName:       job,
+		ContainerID:   container + "0123456789",
+		ContainerName: container,
+		ScheduledAt:   started,
+		StartedAt:     started,
+		FinishedAt:    started.Add(time.Second),
+		Attempt:       1,
+		Outcome:       OutcomeSuccess,
+	}
+}
+
+func historyKeys(t *testing.T, s *Store, query Query) []string {
+	t.Helper()
+	records, err := s.History(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, len(records))
+	for i, record := range records {
+		keys[i] = record.JobKey + "@" + record.StartedAt.Format("15:04")
+	}
+	return keys
+}
+
+func TestHistoryOrderAndFilters(t *testing.T) {
+	s := openTestStore(t, types.StoreConfig{})
+	base := time.Date(2026, time.January, 5, 10, 0, 0, 0, time.UTC)
+
+	// Recorded out of order, history sorts by start time
+	for _, record := range []RunRecord{
+		testRecord("dump", "db", base.Add(2*time.Minute)),
+		testRecord("dump", "db", base),
+		testRecord("sync", "app", base.Add(time.Minute)),
+		testRecord("dump", "db", base.Add(3*time.Minute)),
+	} {
+		if err := s.RecordRun(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all newest first", Query{}, []string{"db/dump@10:03", "db/dump@10:02", "app/sync@10:01", "db/dump@10:00"}},
+		{"limit", Query{Limit: 2}, []string{"db/dump@10:03", "db/dump@10:02"}},
+		{"job key", Query{JobKey: "app/sync"}, []string{"app/sync@10:01"}},
+		{"job id", Query{JobKey: "docker-app-sync"}, []string{"app/sync@10:01"}},
+		{"job name", Query{JobName: "dump", Limit: 1}, []string{"db/dump@10:03"}},
+		{"container name", Query{Container: "app"}, []string{"app/sync@10:01"}},
+		{"container id prefix", Query{Container: "db01"}, []string{"db/dump@10:03", "db/dump@10:02", "db/dump@10:00"}},
+		{"since", Query{Since: base.Add(2 * time.Minute)}, []string{"db/dump@10:03", "db/dump@10:02"}},
+		{"until", Query{Until: base.Add(2 * time.Minute)}, []string{"app/sync@10:01", "db/dump@10:00"}},
+		{"range", Query{Since: base.Add(time.Minute), Until: base.Add(3 * time.Minute)}, []string{"db/dump@10:02", "app/sync@10:01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := historyKeys(t, s, tt.query)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("History = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		config  types.StoreConfig
+		started []time.Duration // Start times relative to now, oldest first
+		want    int
+	}{
+		{"keeps all", types.StoreConfig{}, []time.Duration{-3 * time.Hour, -2 * time.Hour, -time.Hour}, 3},
+		{"by count", types.StoreConfig{MaxRuns: 2}, []time.Duration{-4 * time.Hour, -3 * time.Hour, -2 * time.Hour, -time.Hour}, 2},
+		{"by age", types.StoreConfig{MaxAge: 90 * time.Minute}, []time.Duration{-3 * time.Hour, -2 * time.Hour, -time.Hour, 0}, 2},
+		{"by count and age", types.StoreConfig{MaxRuns: 1, MaxAge: 90 * time.Minute}, []time.Duration{-3 * time.Hour, -time.Hour, 0}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openTestStore(t, tt.config)
+			for _, offset := range tt.started {
+				if err := s.RecordRun(testRecord("dump", "db", now.Add(offset))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			records, err := s.History(Query{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != tt.want {
+				t.Fatalf("kept %d runs, want %d", len(records), tt.want)
+			}
+
+			// The newest runs are the ones kept
+			newest := now.Add(tt.started[len(tt.started)-1])
+			if !records[0].StartedAt.Equal(newest) {
+				t.Errorf("newest kept run started %v, want %v", records[0].StartedAt, newest)
+			}
+		})
+	}
+}
+
+func TestPruneCountSurvivesReopen(t *testing.T) {
+	config := types.StoreConfig{Path: filepath.Join(t.TempDir(), "crontask.db"), MaxRuns: 3}
+	base := time.Now()
+
+	s := openTestStore(t, config)
+	for i := 0; i < 5; i++ {
+		if err := s.RecordRun(testRecord("dump", "db", base.Add(time.Duration(i)*time.Minute))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	s = openTestStore(t, config)
+	if err := s.RecordRun(testRecord("dump", "db", base.Add(5*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+
+	got := historyKeys(t, s, Query{})
+	if len(got) != 3 {
+		t.Errorf("History = %v, want the 3 newest runs", got)
+	}
+}
+
+func TestRecordRunOutputLimit(t *testing.T) {
+	tests := []struct {
+		output    string
+		limit     int
+		want      string
+		truncated bool
+	}{
+		{"hello", 10, "hello", false},
+		{"hello", 5, "hello", false},
+		{"hello world", 5, "hello", true},
+		{"héllo", 2, "h", true}, // é is two bytes, never split
+		{"héllo", 3, "hé", true},
+		{"日本語", 4, "日", true},
+		{"日本語", 0, "日本語", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.output, tt.limit), func(t *testing.T) {
+			s := openTestStore(t, types.StoreConfig{OutputLimit: tt.limit})
+
+			record := testRecord("dump", "db", time.Now())
+			record.Output = tt.output
+			if err := s.RecordRun(record); err != nil {
+				t.Fatal(err)
+			}
+
+			records, err := s.History(Query{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := records[0]
+			if got.Output != tt.want || got.Truncated != tt.truncated {
+				t.Errorf("output = %q (truncated %v), want %q (truncated %v)",
+					got.Output, got.Truncated, tt.want, tt.truncated)
+			}
+			if !utf8.ValidString(got.Output) {
+				t.Errorf("output %q is not valid UTF-8", got.Output)
+			}
+		})
+	}
+}
+
+func TestJobState(t *testing.T) {
+	s := openTestStore(t, types.StoreConfig{})
+	base := time.Date(2026, time.January, 5, 10, 0, 0, 0, time.UTC)
+
+	run := testRecord("dump", "db", base)
+	skipped := testRecord("dump", "db", base.Add(time.Minute))
+	skipped.Outcome = OutcomeSkipped
+	dryRun := testRecord("dump", "db", base.Add(2*time.Minute))
+	dryRun.Outcome = OutcomeDryRun
+
+	for _, record := range []RunRecord{run, skipped, dryRun} {
+		if err := s.RecordRun(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetPaused("db/dump", true); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := s.JobState("db/dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.LastRun.Equal(run.StartedAt) || state.LastOutcome != OutcomeSuccess {
+		t.Errorf("last run = %v %s, want %v success", state.LastRun, state.LastOutcome, run.StartedAt)
+	}
+	if !state.LastScheduled.Equal(dryRun.ScheduledAt) {
+		t.Errorf("last scheduled = %v, want %v", state.LastScheduled, dryRun.ScheduledAt)
+	}
+	if !state.Paused {
+		t.Error("paused = false, want true")
+	}
+
+	if state, err := s.JobState("app/sync"); err != nil || state != nil {
+		t.Errorf("JobState of an unknown job = %v, %v, want nil", state, err)
+	}
+}
+
+func TestUnfinished(t *testing.T) {
+	s := openTestStore(t, types.StoreConfig{})
+
+	started := testRecord("dump", "db", time.Now())
+	finished := testRecord("sync", "app", time.Now())
+	for _, record := range []RunRecord{started, finished} {
+		if err := s.BeginRun(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.RecordRun(finished); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := s.Unfinished()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].JobKey != "db/dump" {
+		t.Errorf("Unfinished = %v, want only db/dump", records)
+	}
+}
@@ -0,0 +1,64 @@
+// internal/tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+)
+
+// Attribute keys shared by crontask spans
+const (
+	JobKey        = attribute.Key("crontask.job.key")
+	JobName       = attribute.Key("crontask.job.name")
+	JobTrigger    = attribute.Key("crontask.job.trigger")
+	RunID         = attribute.Key("crontask.run.id")
+	LockGroup     = attribute.Key("crontask.lock.group")
+	ExecID        = attribute.Key("crontask.exec.id")
+	ExitCode      = attribute.Key("crontask.exec.exit_code")
+	ContainerID   = semconv.ContainerIDKey
+	ContainerName = semconv.ContainerNameKey
+)
+
+// Setup installs the global tracer provider exporting to the configured
+// OTLP endpoint. Tracing stays a no-op when disabled. The returned function
+// flushes and stops the exporter.
+func Setup(ctx context.Context, cfg *types.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
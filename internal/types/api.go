@@ -0,0 +1,30 @@
+package types
+
+// Scopes an API caller may hold, operator includes read
+const (
+	ScopeRead     = "read"
+	ScopeOperator = "operator"
+)
+
+// APIConfig for the HTTP management API
+type APIConfig struct {
+	Enabled bool         `mapstructure:"enabled"`
+	Listen  string       `mapstructure:"listen"` // host:port or unix:///path/to/socket, the local control socket if empty
+	Tokens  []APIToken   `mapstructure:"tokens"` // Bearer tokens, with none and no client CA callers are anonymous
+	TLS     APITLSConfig `mapstructure:"tls"`
+}
+
+// APIToken grants its holder a scope on the management API
+type APIToken struct {
+	Name  string `mapstructure:"name"`  // Caller identity recorded in the audit log
+	Token string `mapstructure:"token"` // Secret sent as "Authorization: Bearer <token>"
+	Scope string `mapstructure:"scope"` // read or operator
+}
+
+// APITLSConfig serves the management API over TLS
+type APITLSConfig struct {
+	CertFile     string `mapstructure:"cert_file"`
+	KeyFile      string `mapstructure:"key_file"`
+	ClientCAFile string `mapstructure:"client_ca_file"` // Verify client certificates against this CA
+	ClientScope  string `mapstructure:"client_scope"`   // Scope of callers identified by certificate: read or operator
+}
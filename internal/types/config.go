@@ -1,11 +1,15 @@
 package types
 
 type Config struct {
-	AppName     string         `mapstructure:"app_name"`
-	Environment string         `mapstructure:"environment"`
-	LogLevel    string         `mapstructure:"log_level"`
-	Worker      WorkerConfig   `mapstructure:"worker"`
-	Docker      DockerConfig   `mapstructure:"docker"`
-	Shutdown    ShutdownConfig `mapstructure:"shutdown"`
-	Logger      LoggerConfig   `mapstructure:"logger"`
+	AppName     string          `mapstructure:"app_name"`
+	Environment string          `mapstructure:"environment"`
+	LogLevel    string          `mapstructure:"log_level"`
+	Worker      WorkerConfig    `mapstructure:"worker"`
+	Scheduler   SchedulerConfig `mapstructure:"scheduler"`
+	Docker      DockerConfig    `mapstructure:"docker"`
+	Store       StoreConfig     `mapstructure:"store"`
+	API         APIConfig       `mapstructure:"api"`
+	Tracing     TracingConfig   `mapstructure:"tracing"`
+	Shutdown    ShutdownConfig  `mapstructure:"shutdown"`
+	Logger      LoggerConfig    `mapstructure:"logger"`
 }
@@ -2,14 +2,63 @@ package types
 
 import "time"
 
+// Trigger kinds of a job
+const (
+	TriggerCron  = "cron"  // Recurring cron expression
+	TriggerAt    = "at"    // Single run at an absolute time
+	TriggerStart = "start" // Run when the container starts
+	TriggerEvent = "event" // Run when another container emits a matching event
+	TriggerChain = "chain" // Run only as part of a dependency chain
+)
+
+// Catch-up policies for runs missed while the daemon was down
+const (
+	CatchUpIgnore = "ignore" // Drop missed runs
+	CatchUpOnce   = "once"   // Run once if any run was missed
+	CatchUpAll    = "all"    // Run every missed occurrence, up to the limit
+)
+
+// Policies for runs cut short by a daemon restart
+const (
+	InterruptIgnore = "ignore" // Only record the run as interrupted
+	InterruptRerun  = "rerun"  // Run the job again once it is registered
+)
+
 // CronJob represents a container-based cron job
 type CronJob struct {
-	ContainerID   string     `json:"container_id"`
-	ContainerName string     `json:"container_name"`
-	CronExpr      string     `json:"cron_expression"`
-	Task          string     `json:"task"`
-	LabelKey      string     `json:"label_key"`
-	IsActive      bool       `json:"is_active"`
-	CreatedAt     time.Time  `json:"created_at"`
-	LastExecution *time.Time `json:"last_execution,omitempty"`
+	ContainerID   string            `json:"container_id"`
+	ContainerName string            `json:"container_name"`
+	Service       string            `json:"service,omitempty"`
+	Image         string            `json:"image,omitempty"`
+	Labels        map[string]string `json:"-"` // Container labels, for task templates
+	Name          string            `json:"name"`
+	Trigger       string            `json:"trigger"`
+	CronExpr      string            `json:"cron_expression,omitempty"`
+	At            time.Time         `json:"at,omitempty"`
+	StartDelay    time.Duration     `json:"start_delay,omitempty"`
+	StartOnce     bool              `json:"start_once,omitempty"`
+	OnEvent       string            `json:"on_event,omitempty"`
+	EventSelector string            `json:"event_selector,omitempty"`
+	EventDebounce time.Duration     `json:"event_debounce,omitempty"`
+	EventRate     string            `json:"event_rate,omitempty"`
+	After         string            `json:"after,omitempty"`
+	OnSuccess     string            `json:"on_success,omitempty"`
+	OnFailure     string            `json:"on_failure,omitempty"`
+	LockGroup     string            `json:"lock_group,omitempty"`
+	LockPolicy    string            `json:"lock_policy,omitempty"`
+	LockTimeout   *time.Duration    `json:"lock_timeout,omitempty"` // nil when not set, 0 waits forever
+	CatchUp       string            `json:"catchup,omitempty"`
+	CatchUpWindow time.Duration     `json:"catchup_window,omitempty"`
+	CatchUpLimit  int               `json:"catchup_limit,omitempty"`
+	Interrupt     string            `json:"interrupt_policy,omitempty"`
+	Timezone      string            `json:"timezone,omitempty"`
+	Jitter        time.Duration     `json:"jitter,omitempty"`
+	AllowWindows  string            `json:"allow_windows,omitempty"`
+	DenyWindows   string            `json:"deny_windows,omitempty"`
+	Task          string            `json:"task"`
+	Exec          ExecOptions       `json:"exec"`
+	LabelKey      string            `json:"label_key"`
+	IsActive      bool              `json:"is_active"`
+	CreatedAt     time.Time         `json:"created_at"`
+	LastExecution *time.Time        `json:"last_execution,omitempty"`
 }
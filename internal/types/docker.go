@@ -8,4 +8,18 @@ type DockerConfig struct {
 	SocketPath   string        `mapstructure:"socket_path"`
 	PollInterval time.Duration `mapstructure:"poll_interval"`
 	LabelPrefix  string        `mapstructure:"label_prefix"`
+
+	Exec            ExecOptions `mapstructure:"exec"`             // Defaults for every job, labels override them
+	AllowPrivileged bool        `mapstructure:"allow_privileged"` // Honour privileged exec labels
+}
+
+// ExecOptions are the settings a job's task is executed with
+type ExecOptions struct {
+	User       string   `mapstructure:"user" json:"user,omitempty"`         // user, uid, user:group or uid:gid
+	Shell      string   `mapstructure:"shell" json:"shell,omitempty"`       // Runs task strings: sh, bash, ash or an absolute path
+	Template   string   `mapstructure:"template" json:"template,omitempty"` // Task rendering: off (default, opt-in), on or strict
+	WorkDir    string   `mapstructure:"workdir" json:"workdir,omitempty"`   // Absolute path inside the container
+	Env        []string `mapstructure:"env" json:"env,omitempty"`           // KEY=value
+	Privileged bool     `mapstructure:"privileged" json:"privileged,omitempty"`
+	Tty        bool     `mapstructure:"tty" json:"tty,omitempty"` // Output is not split into stdout and stderr
 }
@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// SchedulerConfig controls how cron expressions are evaluated
+type SchedulerConfig struct {
+	Timezone    string        `mapstructure:"timezone"`     // IANA zone name, empty or "Local" for the process zone
+	Blackouts   []string      `mapstructure:"blackouts"`    // Windows in which no job runs
+	LockPolicy  string        `mapstructure:"lock_policy"`  // Default lock group policy: wait, skip
+	LockTimeout time.Duration `mapstructure:"lock_timeout"` // Default wait limit for a busy lock group, 0 waits forever
+
+	CatchUp       string        `mapstructure:"catchup"`        // Default policy for runs missed while down: ignore, once, all
+	CatchUpWindow time.Duration `mapstructure:"catchup_window"` // How far back missed runs are considered
+	CatchUpLimit  int           `mapstructure:"catchup_limit"`  // Most missed runs replayed by the "all" policy
+
+	InterruptPolicy string `mapstructure:"interrupt_policy"` // Default for runs cut short by a restart: ignore, rerun
+}
@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// StoreConfig for the on-disk job state and run history
+type StoreConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Path        string        `mapstructure:"path"`         // Database file, auto-detected if empty
+	MaxRuns     int           `mapstructure:"max_runs"`     // Run records kept, 0 keeps all
+	MaxAge      time.Duration `mapstructure:"max_age"`      // Run records older than this are removed, 0 keeps all
+	OutputLimit int           `mapstructure:"output_limit"` // Bytes of task output stored per run
+}
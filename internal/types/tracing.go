@@ -0,0 +1,10 @@
+package types
+
+// TracingConfig for OpenTelemetry traces of job runs
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP collector, host:port
+	Insecure    bool    `mapstructure:"insecure"`     // Plain HTTP instead of HTTPS
+	ServiceName string  `mapstructure:"service_name"` // service.name resource attribute
+	SampleRatio float64 `mapstructure:"sample_ratio"` // Fraction of runs traced, 0 to 1
+}
@@ -6,4 +6,5 @@ type WorkerConfig struct {
 	Interval      time.Duration `mapstructure:"interval"`
 	MaxJobs       int           `mapstructure:"max_jobs"`
 	RetryAttempts int           `mapstructure:"retry_attempts"`
+	DryRun        bool          `mapstructure:"dry_run"` // Discover and schedule jobs but execute nothing
 }
@@ -0,0 +1,151 @@
+// internal/worker/catchup.go
+package worker
+
+import (
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/schedule"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+// catchUp replays the runs a job missed while the daemon was down,
+// following the job's catch-up policy
+func (w *Worker) catchUp(dockerJob *job.DockerJob) {
+	policy, window, limit := w.catchUpPolicy(dockerJob)
+	if policy == types.CatchUpIgnore {
+		return
+	}
+
+	if policy == types.CatchUpOnce {
+		limit = 1
+	}
+
+	state, known := w.jobState(dockerJob)
+	replay, missed := w.missedRuns(dockerJob, state, known, window, limit)
+	if missed == 0 {
+		return
+	}
+
+	w.logger.Info("Catching up missed runs | %s: %s, %s: %s, %s: %d, %s: %d",
+		"job", dockerJob.Name(),
+		"policy", policy,
+		"missed", missed,
+		"replayed", len(replay))
+
+	go func() {
+		for _, scheduled := range replay {
+			select {
+			case <-w.shutdown:
+				return
+			default:
+			}
+
+			run := newJobRun(scheduled)
+			run.catchUp = true
+
+			w.logger.Info("Running missed job | %s: %s, %s: %s, %s: %s",
+				"job", dockerJob.Name(),
+				"run", run.id,
+				"scheduled", scheduled.Format(time.RFC3339))
+
+			if w.isPaused(dockerJob) {
+				w.skipJob(dockerJob, run, "job is paused")
+				continue
+			}
+
+			if reason := w.suppressed(dockerJob, time.Now()); reason != "" {
+				w.skipJob(dockerJob, run, reason)
+				continue
+			}
+
+			w.runChain(dockerJob, run)
+		}
+	}()
+}
+
+// catchUpPolicy returns the job's catch-up settings, falling back to the
+// scheduler defaults
+func (w *Worker) catchUpPolicy(dockerJob *job.DockerJob) (string, time.Duration, int) {
+	policy, window, limit := dockerJob.CatchUp()
+	if policy == "" {
+		policy = w.config.Scheduler.CatchUp
+	}
+	if window == 0 {
+		window = w.config.Scheduler.CatchUpWindow
+	}
+	if limit == 0 {
+		limit = w.config.Scheduler.CatchUpLimit
+	}
+
+	switch policy {
+	case types.CatchUpOnce, types.CatchUpAll:
+	default:
+		policy = types.CatchUpIgnore
+	}
+
+	return policy, window, max(limit, 1)
+}
+
+// missedRuns returns, oldest first, the latest occurrences up to limit
+// since the job's last recorded run that fall inside the catch-up window,
+// and how many were missed in total. Jobs that never ran have nothing to
+// catch up, except one-shot jobs whose time has passed. known tells whether
+// the state came from the store; without it a one-shot job cannot be told
+// apart from one that already ran, so it is not replayed.
+func (w *Worker) missedRuns(dockerJob *job.DockerJob, state *store.JobState, known bool, window time.Duration, limit int) ([]time.Time, int) {
+	now := time.Now()
+
+	var from time.Time
+	if state != nil {
+		from = state.LastScheduled
+		if from.IsZero() {
+			from = state.LastRun
+		}
+	}
+
+	var earliest time.Time
+	if window > 0 {
+		earliest = now.Add(-window)
+	}
+
+	switch dockerJob.Trigger() {
+	case types.TriggerAt:
+		if !known {
+			return nil, 0
+		}
+
+		at := dockerJob.At()
+		if at.After(now) || at.Before(earliest) || (!from.IsZero() && !at.After(from)) {
+			return nil, 0
+		}
+		return []time.Time{at}, 1
+
+	case types.TriggerCron:
+		if from.IsZero() {
+			return nil, 0
+		}
+		if from.Before(earliest) {
+			from = earliest
+		}
+
+		sched, err := schedule.Parse(dockerJob.Schedule())
+		if err != nil {
+			return nil, 0
+		}
+
+		var replay []time.Time
+		missed := 0
+		for t := sched.Next(from.In(w.cron.Location())); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+			missed++
+			replay = append(replay, t)
+			if len(replay) > limit {
+				replay = replay[1:]
+			}
+		}
+		return replay, missed
+	}
+
+	return nil, 0
+}
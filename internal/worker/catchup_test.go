@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/pkg/logger"
+)
+
+func TestMissedRunsAt(t *testing.T) {
+	w := New(&types.Config{Scheduler: types.SchedulerConfig{Timezone: "UTC"}}, logger.New("fatal"))
+
+	at := time.Now().Add(-time.Hour).Truncate(time.Second)
+	oneShot := job.NewDockerJob(types.CronJob{
+		ContainerID:   "db0000000000",
+		ContainerName: "db",
+		Name:          "migrate",
+		Trigger:       types.TriggerAt,
+		At:            at,
+	}, nil)
+
+	tests := []struct {
+		name   string
+		state  *store.JobState
+		known  bool
+		window time.Duration
+		want   int
+	}{
+		{name: "no store", known: false, want: 0},
+		{name: "never ran", known: true, want: 1},
+		{name: "already ran", state: &store.JobState{LastScheduled: at, LastRun: at}, known: true, want: 0},
+		{name: "outside the window", known: true, window: time.Minute, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, missed := w.missedRuns(oneShot, tt.state, tt.known, tt.window, 1)
+			if missed != tt.want || len(replay) != tt.want {
+				t.Fatalf("missedRuns = %v, %d, want %d", replay, missed, tt.want)
+			}
+			if tt.want == 1 && !replay[0].Equal(at) {
+				t.Errorf("replayed %v, want %v", replay[0], at)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// internal/worker/chain.go
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// checkDependencies reports missing upstream jobs and rejects a job that
+// would close a dependency cycle
+func (w *Worker) checkDependencies(dockerJob *job.DockerJob) bool {
+	if len(dockerJob.Dependencies()) == 0 {
+		return true
+	}
+
+	missing, err := w.jobRegistry.CheckDependencies(dockerJob)
+	if err != nil {
+		w.logger.Error("Job rejected | %s, %s: %s, %s: %s",
+			err.Error(),
+			"container", dockerJob.GetContainerID()[:12],
+			"job", dockerJob.Name())
+		return false
+	}
+
+	for _, reason := range missing {
+		w.logger.Warn("Job dependency not registered yet | %s, %s: %s",
+			reason,
+			"job", dockerJob.Name())
+	}
+
+	return true
+}
+
+// runChain runs a job followed by every job that depends on it, in
+// dependency order and under one run ID
+func (w *Worker) runChain(root *job.DockerJob, run *jobRun) {
+	if !w.trackRun() {
+		w.logger.Debug("Worker stopping, run dropped | %s: %s, %s: %s",
+			"job", root.Name(),
+			"run", run.id)
+		return
+	}
+	defer w.runs.Done()
+
+	// One trace per run, starting when the run was due
+	ctx, span := tracer.Start(context.Background(), "run "+root.Key(),
+		trace.WithTimestamp(run.scheduled),
+		trace.WithAttributes(append(jobAttributes(root),
+			tracing.RunID.String(run.id),
+			attribute.Bool("crontask.run.catch_up", run.catchUp),
+			attribute.Bool("crontask.run.manual", run.manual))...))
+	defer span.End()
+
+	_, delay := tracer.Start(ctx, "schedule.delay", trace.WithTimestamp(run.scheduled))
+	delay.End()
+
+	plan := w.jobRegistry.ChainPlan(root)
+
+	// Skipped jobs leave no outcome, so their dependents do not run either
+	outcomes := make(map[*job.DockerJob]error)
+	if err := w.executeJob(ctx, root, run); !errors.Is(err, errJobSkipped) {
+		outcomes[root] = err
+	}
+	if len(plan) == 0 {
+		return
+	}
+
+	for _, step := range plan {
+		if w.isPaused(step.Job) {
+			w.skipJob(step.Job, run, "job is paused")
+			continue
+		}
+
+		if reason := w.chainBlocked(step, outcomes); reason != "" {
+			w.skipJob(step.Job, run, reason)
+			continue
+		}
+
+		if err := w.executeJob(ctx, step.Job, run); !errors.Is(err, errJobSkipped) {
+			outcomes[step.Job] = err
+		}
+	}
+
+	w.logger.Info("Job chain finished | %s: %s, %s: %s, %s: %d",
+		"root", root.Name(),
+		"run", run.id,
+		"jobs", len(outcomes))
+}
+
+// chainBlocked returns why a chained job must not run, empty when it may
+func (w *Worker) chainBlocked(step job.ChainStep, outcomes map[*job.DockerJob]error) string {
+	var reasons []string
+	for _, edge := range step.Upstream {
+		err, ran := outcomes[edge.Job]
+		if !ran {
+			reasons = append(reasons, fmt.Sprintf("%s did not run", edge.Job.Key()))
+			continue
+		}
+		if !edge.Satisfied(err) {
+			reasons = append(reasons, fmt.Sprintf("%s %s, condition %s", edge.Job.Key(), outcomeName(err), edge.Condition))
+		}
+	}
+
+	if len(reasons) > 0 {
+		return strings.Join(reasons, "; ")
+	}
+
+	return w.suppressed(step.Job, time.Now().In(w.jobLocation(step.Job)))
+}
+
+func outcomeName(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "succeeded"
+}
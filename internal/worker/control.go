@@ -0,0 +1,149 @@
+// internal/worker/control.go
+package worker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+)
+
+// ErrJobNotFound is returned when no registered job matches an ID
+var ErrJobNotFound = errors.New("job not found")
+
+// FindJob looks a registered job up by ID (docker-<container>-<name>) or
+// by key (<container name>/<job name>)
+func (w *Worker) FindJob(id string) (*job.DockerJob, error) {
+	if w.jobRegistry == nil {
+		return nil, ErrJobNotFound
+	}
+
+	for _, dockerJob := range w.jobRegistry.GetAllJobs() {
+		if dockerJob.Name() == id || dockerJob.Key() == id {
+			return dockerJob, nil
+		}
+	}
+	return nil, ErrJobNotFound
+}
+
+// GetJob describes one registered job
+func (w *Worker) GetJob(id string) (map[string]interface{}, error) {
+	dockerJob, err := w.FindJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return w.jobInfo(dockerJob), nil
+}
+
+// TriggerJob runs a job and its dependents now, ignoring its schedule,
+// time windows and pause, and returns the run ID
+func (w *Worker) TriggerJob(id string) (string, error) {
+	dockerJob, err := w.FindJob(id)
+	if err != nil {
+		return "", err
+	}
+
+	run := newJobRun(time.Now().In(w.jobLocation(dockerJob)))
+	run.manual = true
+
+	w.logger.Info("Job triggered manually | %s: %s, %s: %s",
+		"job", dockerJob.Name(),
+		"run", run.id)
+
+	go w.runChain(dockerJob, run)
+	return run.id, nil
+}
+
+// PauseJob stops a job from running on its triggers until resumed
+func (w *Worker) PauseJob(id string) error {
+	return w.setPaused(id, true)
+}
+
+// ResumeJob lets a paused job run on its triggers again
+func (w *Worker) ResumeJob(id string) error {
+	return w.setPaused(id, false)
+}
+
+func (w *Worker) setPaused(id string, paused bool) error {
+	dockerJob, err := w.FindJob(id)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if paused {
+		w.paused[dockerJob.Key()] = true
+	} else {
+		delete(w.paused, dockerJob.Key())
+	}
+	w.mu.Unlock()
+
+	// Remembered across restarts when there is a store
+	if w.store != nil {
+		if err := w.store.SetPaused(dockerJob.Key(), paused); err != nil {
+			w.logger.Error("Failed to save paused state | %s, %s: %s",
+				err.Error(),
+				"job", dockerJob.Name())
+		}
+	}
+
+	w.logger.Info("Job paused state changed | %s: %s, %s: %t",
+		"job", dockerJob.Name(),
+		"paused", paused)
+	return nil
+}
+
+func (w *Worker) isPaused(dockerJob *job.DockerJob) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.paused[dockerJob.Key()]
+}
+
+// JobHistory returns a job's most recent runs, newest first
+func (w *Worker) JobHistory(id string, limit int) ([]store.RunRecord, error) {
+	dockerJob, err := w.FindJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return w.History(store.Query{JobKey: dockerJob.Key(), Limit: limit})
+}
+
+// Status describes the daemon, its Docker discovery and its store
+func (w *Worker) Status() map[string]interface{} {
+	containers := make(map[string]bool)
+	jobs := 0
+	if w.jobRegistry != nil {
+		for _, dockerJob := range w.jobRegistry.GetAllJobs() {
+			containers[dockerJob.GetContainerID()] = true
+			jobs++
+		}
+	}
+
+	w.mu.RLock()
+	paused := len(w.paused)
+	w.mu.RUnlock()
+
+	storePath := ""
+	if w.store != nil {
+		storePath = w.store.Path()
+	}
+
+	return map[string]interface{}{
+		"started_at": w.startedAt.Format(time.RFC3339),
+		"uptime":     time.Since(w.startedAt).Truncate(time.Second).String(),
+		"timezone":   w.cron.Location().String(),
+		"dry_run":    w.config.Worker.DryRun,
+		"docker": map[string]interface{}{
+			"enabled":    w.dockerMon != nil,
+			"containers": len(containers),
+			"jobs":       jobs,
+		},
+		"store": map[string]interface{}{
+			"enabled": w.store != nil,
+			"path":    storePath,
+		},
+		"paused_jobs": paused,
+		"stats":       w.GetStats(),
+	}
+}
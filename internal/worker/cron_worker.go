@@ -13,6 +13,7 @@ func (w *Worker) runCron(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	// Start cron scheduler
 	w.cron.Start()
+	w.startHeartbeat()
 	w.logger.Debug("cron worker | cron scheduler started")
 
 	// Wait for shutdown
@@ -28,5 +29,6 @@ func (w *Worker) runCron(ctx context.Context, wg *sync.WaitGroup) {
 
 func (w *Worker) cleanupCron() {
 	w.logger.Debug("cron worker | cleanup")
-	w.cron.Stop()
+	w.stopHeartbeat()
+	<-w.cron.Stop().Done()
 }
@@ -13,12 +13,24 @@ func (w *Worker) runDockerMon(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	// Start Docker monitor if enabled
 	if w.dockerMon != nil {
+		// Settle runs the previous daemon left behind before jobs register
+		w.reconcileRuns()
+
+		// Consume events before the initial scan starts producing them
+		go w.handleDockerEvents(ctx)
+
 		if err := w.dockerMon.Start(ctx); err != nil {
 			w.logger.Error("docker monitor | Failed to start Docker monitor, %s", err.Error())
-		} else {
-			go w.handleDockerEvents(ctx)
 		}
 	}
+
+	// Wait for shutdown
+	select {
+	case <-ctx.Done():
+		w.logger.Debug("docker monitor | received context cancellation")
+	case <-w.shutdown:
+		w.logger.Debug("docker monitor | received shutdown signal")
+	}
 }
 
 func (w *Worker) cleanupDockerMon() {
@@ -0,0 +1,158 @@
+package worker
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/pkg/logger"
+)
+
+// fakeEngine answers the Docker API calls an exec makes. Commands mentioning
+// "false" exit with code 1, commands mentioning "sleep" take a moment to
+// finish, everything else succeeds at once.
+type fakeEngine struct {
+	mu    sync.Mutex
+	execs map[string]int  // Exit code by exec ID
+	slow  map[string]bool // Execs that take slowExec to finish
+}
+
+// How long a "sleep" command runs on the fake engine
+const slowExec = 200 * time.Millisecond
+
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+func startFakeEngine(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "crontask")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := &fakeEngine{execs: make(map[string]int), slow: make(map[string]bool)}
+	server := &http.Server{Handler: engine}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return socket
+}
+
+func (e *fakeEngine) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	path := apiVersionPrefix.ReplaceAllString(r.URL.Path, "")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "/_ping":
+		rw.Header().Set("Api-Version", "1.44")
+		rw.Write([]byte("OK"))
+
+	case len(parts) == 3 && parts[0] == "containers" && parts[2] == "exec":
+		var config struct{ Cmd []string }
+		json.NewDecoder(r.Body).Decode(&config)
+
+		e.mu.Lock()
+		id := fmt.Sprintf("exec%d", len(e.execs)+1)
+		e.execs[id] = 0
+		command := strings.Join(config.Cmd, " ")
+		if strings.Contains(command, "false") {
+			e.execs[id] = 1
+		}
+		e.slow[id] = strings.Contains(command, "sleep")
+		e.mu.Unlock()
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		json.NewEncoder(rw).Encode(map[string]string{"Id": id})
+
+	case len(parts) == 3 && parts[0] == "exec" && parts[2] == "start":
+		conn, buf, err := rw.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		e.mu.Lock()
+		slow := e.slow[parts[1]]
+		e.mu.Unlock()
+		if slow {
+			time.Sleep(slowExec)
+		}
+
+		buf.WriteString("HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.raw-stream\r\n" +
+			"Connection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+
+		// One stdout frame of the multiplexed stream
+		output := []byte("done\n")
+		header := make([]byte, 8)
+		header[0] = 1
+		binary.BigEndian.PutUint32(header[4:], uint32(len(output)))
+		buf.Write(header)
+		buf.Write(output)
+		buf.Flush()
+
+	case len(parts) == 3 && parts[0] == "exec" && parts[2] == "json":
+		e.mu.Lock()
+		exitCode := e.execs[parts[1]]
+		e.mu.Unlock()
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{"ID": parts[1], "Running": false, "ExitCode": exitCode})
+
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+// newTestWorker returns a worker talking to a fake engine, with configure
+// adjusting the config first when set
+func newTestWorker(t *testing.T, configure func(cfg *types.Config)) *Worker {
+	t.Helper()
+
+	cfg := &types.Config{
+		Scheduler: types.SchedulerConfig{Timezone: "UTC", LockPolicy: LockPolicyWait},
+		Docker:    types.DockerConfig{Enabled: true, SocketPath: startFakeEngine(t), LabelPrefix: "crontask.", PollInterval: time.Second},
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	w := New(cfg, logger.New("fatal"))
+	if w.dockerMon == nil {
+		t.Fatal("worker did not connect to the fake engine")
+	}
+	return w
+}
+
+func addTestJob(t *testing.T, w *Worker, cronJob types.CronJob) *job.DockerJob {
+	t.Helper()
+
+	cronJob.ContainerID = cronJob.ContainerName + strings.Repeat("0", 64-len(cronJob.ContainerName))
+	if cronJob.Trigger == "" {
+		cronJob.Trigger = types.TriggerChain
+	}
+
+	dockerJob := job.NewDockerJob(cronJob, w.dockerMon)
+	if !w.jobRegistry.AddJob(dockerJob) {
+		t.Fatalf("duplicate job %s", dockerJob.Key())
+	}
+	return dockerJob
+}
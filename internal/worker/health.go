@@ -0,0 +1,178 @@
+// internal/worker/health.go
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+	"github.com/robfig/cron/v3"
+)
+
+// Loops report in this often, missing three beats counts as stalled
+const (
+	heartbeatInterval = 5 * time.Second
+	heartbeatStale    = 3 * heartbeatInterval
+)
+
+// HealthCheck is the state of one subsystem
+type HealthCheck struct {
+	Name        string `json:"name"`
+	OK          bool   `json:"ok"`
+	Error       string `json:"error,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+	LastErrorAt string `json:"last_error_at,omitempty"`
+}
+
+// health holds heartbeats and the last failure of the checks the worker runs
+type health struct {
+	mu           sync.Mutex
+	cronRunning  bool
+	cronBeat     time.Time
+	eventBeat    time.Time
+	heartbeatID  cron.EntryID
+	lastErrors   map[string]string
+	lastErrorsAt map[string]time.Time
+}
+
+func newHealth() *health {
+	return &health{
+		lastErrors:   make(map[string]string),
+		lastErrorsAt: make(map[string]time.Time),
+	}
+}
+
+// check builds a check result, remembering failures
+func (h *health) check(name string, err error) HealthCheck {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := HealthCheck{Name: name, OK: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+		h.lastErrors[name] = err.Error()
+		h.lastErrorsAt[name] = time.Now()
+	}
+
+	if at, ok := h.lastErrorsAt[name]; ok {
+		result.LastError = h.lastErrors[name]
+		result.LastErrorAt = at.Format(time.RFC3339)
+	}
+	return result
+}
+
+func (h *health) beat(at *time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*at = time.Now()
+}
+
+// stale returns an error when a heartbeat is too old
+func (h *health) stale(name string, at *time.Time) error {
+	h.mu.Lock()
+	last := *at
+	h.mu.Unlock()
+
+	if last.IsZero() {
+		return fmt.Errorf("%s has not reported yet", name)
+	}
+	if age := time.Since(last); age > heartbeatStale {
+		return fmt.Errorf("%s stalled, last heartbeat %s ago", name, age.Truncate(time.Second))
+	}
+	return nil
+}
+
+// startHeartbeat schedules the cron entry proving the scheduler still fires
+func (w *Worker) startHeartbeat() {
+	w.health.mu.Lock()
+	defer w.health.mu.Unlock()
+
+	w.health.cronRunning = true
+	w.health.cronBeat = time.Now()
+	w.health.heartbeatID = w.cron.Schedule(cron.Every(heartbeatInterval), cron.FuncJob(func() {
+		w.health.beat(&w.health.cronBeat)
+	}))
+}
+
+func (h *health) heartbeatEntry() cron.EntryID {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.heartbeatID
+}
+
+func (w *Worker) stopHeartbeat() {
+	w.health.mu.Lock()
+	defer w.health.mu.Unlock()
+
+	w.health.cronRunning = false
+	w.cron.Remove(w.health.heartbeatID)
+}
+
+// Liveness reports whether the scheduler and the event loop still make
+// progress
+func (w *Worker) Liveness() []HealthCheck {
+	checks := []HealthCheck{
+		w.health.check("scheduler", w.health.stale("scheduler", &w.health.cronBeat)),
+	}
+
+	if w.dockerMon != nil {
+		checks = append(checks,
+			w.health.check("event_loop", w.health.stale("event loop", &w.health.eventBeat)))
+	}
+
+	return checks
+}
+
+// Readiness reports whether the daemon can discover and run jobs: Docker
+// answers, events are subscribed, the initial scan is done and the
+// scheduler runs
+func (w *Worker) Readiness(ctx context.Context) []HealthCheck {
+	var checks []HealthCheck
+
+	if w.config.Docker.Enabled {
+		if w.dockerMon == nil {
+			err := fmt.Errorf("docker monitor is not available")
+			checks = append(checks,
+				w.health.check("docker", err),
+				w.health.check("event_subscription", err),
+				w.health.check("initial_scan", err))
+		} else {
+			pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			checks = append(checks, w.health.check("docker", w.dockerMon.Ping(pingCtx)))
+			cancel()
+
+			checks = append(checks,
+				subsystemCheck("event_subscription", "events are not subscribed", w.dockerMon.EventStatus()),
+				subsystemCheck("initial_scan", "initial scan has not completed", w.dockerMon.ScanStatus()))
+		}
+	}
+
+	w.health.mu.Lock()
+	running := w.health.cronRunning
+	w.health.mu.Unlock()
+
+	var err error
+	if !running {
+		err = fmt.Errorf("scheduler is not running")
+	}
+	checks = append(checks, w.health.check("scheduler", err))
+
+	return checks
+}
+
+// subsystemCheck turns a monitor status into a check
+func subsystemCheck(name string, failure string, status docker.SubsystemStatus) HealthCheck {
+	check := HealthCheck{Name: name, OK: status.OK, LastError: status.LastError}
+	if !status.OK {
+		check.Error = failure
+		if status.LastError != "" {
+			check.Error = status.LastError
+		}
+	}
+	if !status.LastErrorAt.IsZero() {
+		check.LastErrorAt = status.LastErrorAt.Format(time.RFC3339)
+	}
+	return check
+}
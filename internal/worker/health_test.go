@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+)
+
+func TestHealthStale(t *testing.T) {
+	tests := []struct {
+		name string
+		beat time.Time
+		want string
+	}{
+		{name: "never reported", want: "has not reported yet"},
+		{name: "fresh", beat: time.Now()},
+		{name: "just within the limit", beat: time.Now().Add(-heartbeatStale + time.Second)},
+		{name: "stalled", beat: time.Now().Add(-heartbeatStale - 5*time.Second), want: "stalled, last heartbeat 20s ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHealth()
+			h.eventBeat = tt.beat
+
+			err := h.stale("event loop", &h.eventBeat)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("stale() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("stale() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthBeat(t *testing.T) {
+	h := newHealth()
+	h.cronBeat = time.Now().Add(-time.Hour)
+	if err := h.stale("scheduler", &h.cronBeat); err == nil {
+		t.Fatal("stale() = nil for an hour old heartbeat")
+	}
+
+	h.beat(&h.cronBeat)
+	if err := h.stale("scheduler", &h.cronBeat); err != nil {
+		t.Errorf("stale() after beat = %v, want nil", err)
+	}
+}
+
+func TestHealthCheckKeepsLastError(t *testing.T) {
+	h := newHealth()
+
+	if got := h.check("docker", nil); !got.OK || got.Error != "" || got.LastError != "" || got.LastErrorAt != "" {
+		t.Fatalf("check() before any failure = %+v, want a clean pass", got)
+	}
+
+	before := time.Now().Truncate(time.Second)
+	failed := h.check("docker", errors.New("connection refused"))
+	if failed.OK || failed.Error != "connection refused" || failed.LastError != "connection refused" {
+		t.Fatalf("check() on failure = %+v, want the error reported", failed)
+	}
+	at, err := time.Parse(time.RFC3339, failed.LastErrorAt)
+	if err != nil {
+		t.Fatalf("LastErrorAt = %q: %v", failed.LastErrorAt, err)
+	}
+	if at.Before(before) || at.After(time.Now()) {
+		t.Errorf("LastErrorAt = %v, want the time of the failure", at)
+	}
+
+	// A later success clears the error but keeps the last failure
+	recovered := h.check("docker", nil)
+	want := HealthCheck{Name: "docker", OK: true, LastError: "connection refused", LastErrorAt: failed.LastErrorAt}
+	if recovered != want {
+		t.Errorf("check() after recovery = %+v, want %+v", recovered, want)
+	}
+
+	// Failures are remembered per check
+	if other := h.check("scheduler", nil); other.LastError != "" {
+		t.Errorf("check(scheduler) = %+v, want no last error", other)
+	}
+}
+
+func TestSubsystemCheck(t *testing.T) {
+	failedAt := time.Date(2026, time.March, 8, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status docker.SubsystemStatus
+		want   HealthCheck
+	}{
+		{
+			name:   "ok",
+			status: docker.SubsystemStatus{OK: true},
+			want:   HealthCheck{Name: "initial_scan", OK: true},
+		},
+		{
+			name:   "not ready without an error",
+			status: docker.SubsystemStatus{},
+			want:   HealthCheck{Name: "initial_scan", Error: "initial scan has not completed"},
+		},
+		{
+			name:   "failing",
+			status: docker.SubsystemStatus{LastError: "list containers: timeout", LastErrorAt: failedAt},
+			want: HealthCheck{
+				Name:        "initial_scan",
+				Error:       "list containers: timeout",
+				LastError:   "list containers: timeout",
+				LastErrorAt: "2026-03-08T02:00:00Z",
+			},
+		},
+		{
+			name:   "recovered",
+			status: docker.SubsystemStatus{OK: true, LastError: "list containers: timeout", LastErrorAt: failedAt},
+			want: HealthCheck{
+				Name:        "initial_scan",
+				OK:          true,
+				LastError:   "list containers: timeout",
+				LastErrorAt: "2026-03-08T02:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := subsystemCheck("initial_scan", "initial scan has not completed", tt.status)
+			if got != tt.want {
+				t.Errorf("subsystemCheck() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
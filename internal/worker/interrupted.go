@@ -0,0 +1,161 @@
+// internal/worker/interrupted.go
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/metrics"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"github.com/amir-mohammad-HP/crontask/pkg/docker"
+)
+
+// Exit codes above this mean the task was killed by a signal
+const signalExitCode = 128
+
+// reconcileRuns settles the runs a previous daemon left unfinished by
+// asking Docker what became of their execs
+func (w *Worker) reconcileRuns() {
+	if w.store == nil || w.dockerMon == nil {
+		return
+	}
+
+	records, err := w.store.Unfinished()
+	if err != nil {
+		w.logger.Error("Failed to load unfinished runs | %s", err.Error())
+		return
+	}
+
+	for _, record := range records {
+		status, err := w.dockerMon.InspectExec(record.ExecID)
+		switch {
+		case errors.Is(err, docker.ErrExecNotFound):
+			record.Outcome = store.OutcomeUnknown
+			record.Reason = "exec no longer exists, outcome unknown"
+
+		case err != nil:
+			// Left for the next startup
+			w.logger.Warn("Failed to reconcile run | %s, %s: %s, %s: %s",
+				err.Error(),
+				"job", record.JobID,
+				"run", record.RunID)
+			continue
+
+		case status.Running:
+			w.logger.Info("Run still in progress after restart | %s: %s, %s: %s",
+				"job", record.JobID,
+				"run", record.RunID)
+			go w.awaitExec(record)
+			continue
+
+		default:
+			settleRun(&record, status)
+		}
+
+		w.finishReconciled(record)
+	}
+}
+
+// settleRun sets the outcome of a finished exec
+func settleRun(record *store.RunRecord, status *docker.ExecStatus) {
+	record.ExitCode = status.ExitCode
+
+	switch {
+	case status.Pid == 0:
+		record.Outcome = store.OutcomeInterrupted
+		record.Reason = "exec never started"
+	case status.ExitCode > signalExitCode:
+		record.Outcome = store.OutcomeInterrupted
+		record.Reason = fmt.Sprintf("killed by signal %d", status.ExitCode-signalExitCode)
+	case status.ExitCode == 0:
+		record.Outcome = store.OutcomeSuccess
+		record.Reason = "completed while crontask was down"
+	default:
+		record.Outcome = store.OutcomeFailed
+		record.Reason = fmt.Sprintf("exited with code %d while crontask was down", status.ExitCode)
+	}
+}
+
+// awaitExec polls an exec that outlived the previous daemon until it ends
+func (w *Worker) awaitExec(record store.RunRecord) {
+	if !w.trackRun() {
+		return
+	}
+	defer w.runs.Done()
+
+	ticker := time.NewTicker(w.config.Docker.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.shutdown:
+			return
+		}
+
+		status, err := w.dockerMon.InspectExec(record.ExecID)
+		if errors.Is(err, docker.ErrExecNotFound) {
+			record.Outcome = store.OutcomeUnknown
+			record.Reason = "exec no longer exists, outcome unknown"
+		} else if err != nil || status.Running {
+			continue
+		} else {
+			settleRun(&record, status)
+		}
+
+		w.finishReconciled(record)
+		return
+	}
+}
+
+// finishReconciled records a settled run and queues interrupted jobs
+// for a rerun once they are registered again
+func (w *Worker) finishReconciled(record store.RunRecord) {
+	record.FinishedAt = time.Now()
+	metrics.RunRecorded(recordMetrics(record), record.Outcome)
+	w.recordRun(record)
+
+	w.logger.Info("Reconciled unfinished run | %s: %s, %s: %s, %s: %s, %s: %s",
+		"job", record.JobID,
+		"run", record.RunID,
+		"outcome", record.Outcome,
+		"reason", record.Reason)
+
+	if record.Outcome == store.OutcomeInterrupted {
+		w.mu.Lock()
+		w.interrupted[record.JobKey] = record
+		w.mu.Unlock()
+	}
+}
+
+// rerunInterrupted runs a newly registered job again if its last run was
+// interrupted and its policy asks for it
+func (w *Worker) rerunInterrupted(dockerJob *job.DockerJob) {
+	w.mu.Lock()
+	record, ok := w.interrupted[dockerJob.Key()]
+	delete(w.interrupted, dockerJob.Key())
+	w.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	policy := dockerJob.InterruptPolicy()
+	if policy == "" {
+		policy = w.config.Scheduler.InterruptPolicy
+	}
+	if policy != types.InterruptRerun {
+		return
+	}
+
+	run := newJobRun(time.Now().In(w.jobLocation(dockerJob)))
+	w.logger.Info("Re-running interrupted job | %s: %s, %s: %s, %s: %s",
+		"job", dockerJob.Name(),
+		"run", run.id,
+		"interrupted_run", record.RunID)
+
+	go w.dispatchRun(dockerJob, run)
+}
@@ -0,0 +1,110 @@
+// internal/worker/lock_groups.go
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Lock group policies
+const (
+	LockPolicyWait = "wait" // Wait for the group, up to the lock timeout
+	LockPolicySkip = "skip" // Skip the run when the group is busy
+)
+
+// lockGroups serializes runs of jobs sharing a named group
+type lockGroups struct {
+	mu     sync.Mutex
+	groups map[string]*lockGroup
+}
+
+type lockGroup struct {
+	slot   chan struct{}
+	holder string
+	since  time.Time
+}
+
+func newLockGroups() *lockGroups {
+	return &lockGroups{groups: make(map[string]*lockGroup)}
+}
+
+func (lg *lockGroups) group(name string) *lockGroup {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+
+	group, ok := lg.groups[name]
+	if !ok {
+		group = &lockGroup{slot: make(chan struct{}, 1)}
+		lg.groups[name] = group
+	}
+	return group
+}
+
+// acquire takes the group for holder according to policy and returns the
+// function that releases it
+func (lg *lockGroups) acquire(name string, holder string, policy string, timeout time.Duration, shutdown <-chan struct{}) (func(), error) {
+	group := lg.group(name)
+
+	select {
+	case group.slot <- struct{}{}:
+	default:
+		if policy == LockPolicySkip {
+			return nil, fmt.Errorf("lock group %q held by %s", name, lg.holder(group))
+		}
+
+		var expired <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		select {
+		case group.slot <- struct{}{}:
+		case <-expired:
+			return nil, fmt.Errorf("timed out after %s waiting for lock group %q held by %s",
+				timeout, name, lg.holder(group))
+		case <-shutdown:
+			return nil, fmt.Errorf("shutting down while waiting for lock group %q", name)
+		}
+	}
+
+	lg.mu.Lock()
+	group.holder = holder
+	group.since = time.Now()
+	lg.mu.Unlock()
+
+	return func() {
+		lg.mu.Lock()
+		group.holder = ""
+		group.since = time.Time{}
+		lg.mu.Unlock()
+		<-group.slot
+	}, nil
+}
+
+func (lg *lockGroups) holder(group *lockGroup) string {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	return group.holder
+}
+
+// snapshot returns the current holder of every known group
+func (lg *lockGroups) snapshot() map[string]interface{} {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+
+	result := make(map[string]interface{}, len(lg.groups))
+	for name, group := range lg.groups {
+		if group.holder == "" {
+			result[name] = map[string]interface{}{"holder": nil}
+			continue
+		}
+		result[name] = map[string]interface{}{
+			"holder": group.holder,
+			"since":  group.since.Format(time.RFC3339),
+		}
+	}
+	return result
+}
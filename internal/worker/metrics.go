@@ -0,0 +1,55 @@
+// internal/worker/metrics.go
+package worker
+
+import (
+	"errors"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/metrics"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+)
+
+// recordMetrics returns the metric labels of the job that made a run
+func recordMetrics(record store.RunRecord) metrics.Job {
+	return metrics.Job{
+		Name:      record.JobKey,
+		Container: record.ContainerName,
+		Service:   record.Service,
+	}
+}
+
+// jobMetrics returns the metric labels of a registered job
+func jobMetrics(dockerJob *job.DockerJob) metrics.Job {
+	return metrics.Job{
+		Name:      dockerJob.Key(),
+		Container: dockerJob.GetContainerName(),
+		Service:   dockerJob.Service(),
+	}
+}
+
+// forgetRemovedJobs drops the series of jobs that are no longer
+// registered. A job registered again under the same key, as when a
+// container's labels are read again, keeps them.
+func (w *Worker) forgetRemovedJobs(jobs ...*job.DockerJob) {
+	for _, dockerJob := range jobs {
+		if _, err := w.FindJob(dockerJob.Key()); errors.Is(err, ErrJobNotFound) {
+			metrics.RemoveJob(jobMetrics(dockerJob))
+		}
+	}
+}
+
+// updateRegistryMetrics publishes how many jobs and containers are registered
+func (w *Worker) updateRegistryMetrics() {
+	if w.jobRegistry == nil {
+		return
+	}
+
+	containers := make(map[string]bool)
+	jobs := w.jobRegistry.GetAllJobs()
+	for _, dockerJob := range jobs {
+		containers[dockerJob.GetContainerID()] = true
+	}
+
+	metrics.RegisteredJobs.Set(float64(len(jobs)))
+	metrics.RegisteredContainers.Set(float64(len(containers)))
+}
@@ -0,0 +1,255 @@
+// internal/worker/run.go
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/metrics"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/amir-mohammad-HP/crontask/internal/worker")
+
+// jobRun describes one run of a job, shared by every job of a chain
+type jobRun struct {
+	id        string
+	scheduled time.Time
+	catchUp   bool // Replays a run missed while the daemon was down
+	manual    bool // Triggered through the API
+}
+
+func newJobRun(scheduled time.Time) *jobRun {
+	return &jobRun{id: job.NewRunID(), scheduled: scheduled}
+}
+
+// context describes the run to the job executing it
+func (run *jobRun) context() job.RunContext {
+	return job.RunContext{ID: run.id, Scheduled: run.scheduled, Attempt: 1}
+}
+
+// executeJob runs a job once inside its lock group and records the run
+func (w *Worker) executeJob(ctx context.Context, job *job.DockerJob, run *jobRun) error {
+	ctx, span := tracer.Start(ctx, "job "+job.Key(), trace.WithAttributes(jobAttributes(job)...))
+	defer span.End()
+
+	if group, policy, jobTimeout := job.LockGroup(); group != "" {
+		if policy == "" {
+			policy = w.config.Scheduler.LockPolicy
+		}
+		timeout := w.config.Scheduler.LockTimeout
+		if jobTimeout != nil {
+			timeout = *jobTimeout
+		}
+
+		_, lockSpan := tracer.Start(ctx, "lock.wait", trace.WithAttributes(tracing.LockGroup.String(group)))
+		release, err := w.locks.acquire(group, job.Name(), policy, timeout, w.shutdown)
+		lockSpan.End()
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			w.skipJob(job, run, err.Error())
+			return errJobSkipped
+		}
+		defer release()
+	}
+
+	started := time.Now()
+	w.logger.Info("Executing job | %s: %s,  %s: %s,  %s: %s,  %s: %s",
+		"job", job.Name(),
+		"container", job.GetContainerID()[:12],
+		"run", run.id,
+		"time", started.Format("2006-01-02 15:04:05"))
+
+	record := w.newRunRecord(job, run, started)
+	record.Attempt = 1
+	metrics.RunStarted(recordMetrics(record), run.scheduled, started)
+
+	if w.config.Worker.DryRun {
+		w.dryRun(job, run.context(), record)
+		return nil
+	}
+
+	result, err := job.Execute(ctx, run.context(), func(execID string) {
+		span.SetAttributes(tracing.ExecID.String(execID))
+		record.ExecID = execID
+		w.beginRun(record)
+	})
+
+	record.FinishedAt = time.Now()
+	record.ExitCode = -1
+	if result != nil {
+		record.ExitCode = result.ExitCode
+		record.Output = result.Output
+	}
+
+	span.SetAttributes(tracing.ExitCode.Int(record.ExitCode))
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		record.Outcome = store.OutcomeFailed
+		record.Reason = err.Error()
+		w.logger.Error("Job execution failed | %s, %s: %s, %s: %s, %s: %s",
+			err.Error(),
+			"job", job.Name(),
+			"container", job.GetContainerID()[:12],
+			"run", run.id)
+	} else {
+		record.Outcome = store.OutcomeSuccess
+		w.logger.Info("Job executed successfully | %s: %s, %s: %s, %s: %s",
+			"job", job.Name(),
+			"container", job.GetContainerID()[:12],
+			"run", run.id)
+	}
+
+	metrics.RunFinished(recordMetrics(record), record.Outcome, record.FinishedAt.Sub(started))
+	w.recordRun(record)
+	// A job removed while running must not leave its series behind
+	w.forgetRemovedJobs(job)
+	return err
+}
+
+// dryRun stands in for executing a job in dry-run mode, logging and
+// recording what would have run
+func (w *Worker) dryRun(job *job.DockerJob, run job.RunContext, record store.RunRecord) {
+	task, err := job.RenderTask(run)
+	if err != nil {
+		task = job.Task()
+	}
+
+	w.logger.Info("Dry run, task not executed | %s: %s, %s: %s, %s: %s, %s: %s, %s: %s",
+		"job", job.Name(),
+		"container", job.GetContainerName(),
+		"run", run.ID,
+		"scheduled", run.Scheduled.Format(time.RFC3339),
+		"task", task)
+
+	record.FinishedAt = record.StartedAt
+	record.Outcome = store.OutcomeDryRun
+	record.Reason = "dry run, would execute: " + task
+	metrics.RunFinished(recordMetrics(record), record.Outcome, 0)
+	w.recordRun(record)
+}
+
+// jobAttributes describes a job on its spans
+func jobAttributes(job *job.DockerJob) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		tracing.JobKey.String(job.Key()),
+		tracing.JobName.String(job.JobName()),
+		tracing.JobTrigger.String(job.Trigger()),
+		tracing.ContainerID.String(job.GetContainerID()),
+		tracing.ContainerName.String(job.GetContainerName()),
+	}
+}
+
+// skipJob records a run that did not happen and why
+func (w *Worker) skipJob(job *job.DockerJob, run *jobRun, reason string) {
+	now := time.Now()
+	job.RecordSkip(now, reason)
+
+	w.logger.Info("Job skipped | %s: %s, %s: %s, %s: %s, %s: %s",
+		"job", job.Name(),
+		"container", job.GetContainerID()[:12],
+		"run", run.id,
+		"reason", reason)
+
+	record := w.newRunRecord(job, run, now)
+	record.FinishedAt = now
+	record.Outcome = store.OutcomeSkipped
+	record.Reason = reason
+	metrics.RunRecorded(recordMetrics(record), record.Outcome)
+	w.recordRun(record)
+}
+
+func (w *Worker) newRunRecord(job *job.DockerJob, run *jobRun, started time.Time) store.RunRecord {
+	return store.RunRecord{
+		RunID:         run.id,
+		JobID:         job.Name(),
+		JobKey:        job.Key(),
+		JobName:       job.JobName(),
+		ContainerID:   job.GetContainerID(),
+		ContainerName: job.GetContainerName(),
+		Service:       job.Service(),
+		Trigger:       job.Trigger(),
+		ScheduledAt:   run.scheduled,
+		StartedAt:     started,
+		CatchUp:       run.catchUp,
+		Manual:        run.manual,
+	}
+}
+
+// beginRun marks a run as in flight so a restart can reconcile it
+func (w *Worker) beginRun(record store.RunRecord) {
+	if w.store == nil {
+		return
+	}
+
+	if err := w.store.BeginRun(record); err != nil {
+		w.logger.Error("Failed to record run start | %s, %s: %s, %s: %s",
+			err.Error(),
+			"job", record.JobID,
+			"run", record.RunID)
+	}
+}
+
+// recordRun saves a run to the history store, if there is one
+func (w *Worker) recordRun(record store.RunRecord) {
+	if w.store == nil {
+		return
+	}
+
+	if err := w.store.RecordRun(record); err != nil {
+		w.logger.Error("Failed to record run | %s, %s: %s, %s: %s",
+			err.Error(),
+			"job", record.JobID,
+			"run", record.RunID)
+	}
+}
+
+// restoreJobState loads what the store remembers about a job
+func (w *Worker) restoreJobState(dockerJob *job.DockerJob) {
+	state, _ := w.jobState(dockerJob)
+	if state == nil {
+		return
+	}
+
+	if !state.LastRun.IsZero() {
+		dockerJob.SetLastRun(state.LastRun)
+	}
+	if state.Paused {
+		w.mu.Lock()
+		w.paused[dockerJob.Key()] = true
+		w.mu.Unlock()
+	}
+}
+
+// jobState returns the job's saved state, nil when nothing is known. The
+// second result is false when there is no store to ask, or it failed, so a
+// nil state does not mean the job never ran.
+func (w *Worker) jobState(dockerJob *job.DockerJob) (*store.JobState, bool) {
+	if w.store == nil {
+		return nil, false
+	}
+
+	state, err := w.store.JobState(dockerJob.Key())
+	if err != nil {
+		w.logger.Warn("Failed to load job state | %s, %s: %s",
+			err.Error(),
+			"job", dockerJob.Name())
+		return nil, false
+	}
+	return state, true
+}
+
+// History returns recorded runs matching the query, newest first
+func (w *Worker) History(query store.Query) ([]store.RunRecord, error) {
+	if w.store == nil {
+		return nil, store.ErrNoStore
+	}
+	return w.store.History(query)
+}
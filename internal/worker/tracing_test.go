@@ -0,0 +1,209 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	exporter        = tracetest.NewInMemoryExporter()
+	installExporter sync.Once
+)
+
+// newTracedWorker returns a worker talking to a fake engine with every span
+// recorded in memory
+func newTracedWorker(t *testing.T) (*Worker, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	// The package tracer binds to the first global provider set, so every
+	// test shares one provider and starts from an empty exporter
+	installExporter.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	})
+	exporter.Reset()
+
+	return newTestWorker(t, nil), exporter
+}
+
+// spanTree indexes recorded spans by name
+type spanTree map[string]tracetest.SpanStub
+
+func recordedSpans(t *testing.T, exporter *tracetest.InMemoryExporter) (spanTree, tracetest.SpanStubs) {
+	t.Helper()
+
+	spans := exporter.GetSpans()
+	tree := make(spanTree)
+	for _, span := range spans {
+		tree[span.Name] = span
+	}
+	return tree, spans
+}
+
+func (tree spanTree) get(t *testing.T, name string) tracetest.SpanStub {
+	t.Helper()
+
+	span, ok := tree[name]
+	if !ok {
+		names := make([]string, 0, len(tree))
+		for name := range tree {
+			names = append(names, name)
+		}
+		t.Fatalf("no span %q, recorded %v", name, names)
+	}
+	return span
+}
+
+func assertChild(t *testing.T, parent, child tracetest.SpanStub) {
+	t.Helper()
+
+	if child.Parent.SpanID() != parent.SpanContext.SpanID() {
+		t.Errorf("span %q is not a child of %q", child.Name, parent.Name)
+	}
+	if child.SpanContext.TraceID() != parent.SpanContext.TraceID() {
+		t.Errorf("span %q is not in the trace of %q", child.Name, parent.Name)
+	}
+}
+
+// childrenOf returns the spans directly under parent
+func childrenOf(spans tracetest.SpanStubs, parent tracetest.SpanStub) []tracetest.SpanStub {
+	var children []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Parent.SpanID() == parent.SpanContext.SpanID() {
+			children = append(children, span)
+		}
+	}
+	return children
+}
+
+func TestTraceChain(t *testing.T) {
+	w, exporter := newTracedWorker(t)
+
+	dump := addTestJob(t, w, types.CronJob{ContainerName: "db", Name: "dump", Task: "pg_dump", Trigger: types.TriggerCron})
+	addTestJob(t, w, types.CronJob{ContainerName: "db", Name: "upload", Task: "false", OnSuccess: "dump"})
+	addTestJob(t, w, types.CronJob{ContainerName: "app", Name: "notify", Task: "echo sent", After: "db/upload"})
+
+	scheduled := time.Now().Add(-2 * time.Second)
+	w.runChain(dump, newJobRun(scheduled))
+
+	tree, spans := recordedSpans(t, exporter)
+
+	root := tree.get(t, "run db/dump")
+	if root.Parent.IsValid() {
+		t.Errorf("span %q has a parent, want a new trace", root.Name)
+	}
+	if !root.StartTime.Equal(scheduled) {
+		t.Errorf("span %q starts at %v, want the scheduled time %v", root.Name, root.StartTime, scheduled)
+	}
+
+	delay := tree.get(t, "schedule.delay")
+	assertChild(t, root, delay)
+	if d := delay.EndTime.Sub(delay.StartTime); d < 2*time.Second {
+		t.Errorf("span %q lasted %v, want the 2s the run was late", delay.Name, d)
+	}
+
+	// Every job of the chain is a child of the run, in dependency order
+	var jobs []string
+	for _, span := range childrenOf(spans, root) {
+		if strings.HasPrefix(span.Name, "job ") {
+			jobs = append(jobs, span.Name)
+		}
+	}
+	if want := []string{"job db/dump", "job db/upload", "job app/notify"}; fmt.Sprint(jobs) != fmt.Sprint(want) {
+		t.Errorf("job spans = %v, want %v", jobs, want)
+	}
+
+	for _, name := range []string{"job db/dump", "job db/upload", "job app/notify"} {
+		jobSpan := tree.get(t, name)
+		assertChild(t, root, jobSpan)
+
+		var calls []string
+		for _, call := range childrenOf(spans, jobSpan) {
+			calls = append(calls, call.Name)
+		}
+		if want := []string{"docker.exec_create", "docker.exec_attach", "docker.exec_inspect"}; fmt.Sprint(calls) != fmt.Sprint(want) {
+			t.Errorf("span %q docker calls = %v, want %v", name, calls, want)
+		}
+	}
+
+	// The failing job carries the error, the others do not
+	for name, want := range map[string]codes.Code{
+		"job db/dump":    codes.Unset,
+		"job db/upload":  codes.Error,
+		"job app/notify": codes.Unset,
+	} {
+		jobSpan := tree.get(t, name)
+		if jobSpan.Status.Code != want {
+			t.Errorf("span %q status = %v, want %v", name, jobSpan.Status.Code, want)
+		}
+	}
+	if status := tree.get(t, "job db/upload").Status; !strings.Contains(status.Description, "exited with code 1") {
+		t.Errorf("error status = %q, want the exit code", status.Description)
+	}
+}
+
+func TestTraceLockWait(t *testing.T) {
+	w, exporter := newTracedWorker(t)
+
+	backup := addTestJob(t, w, types.CronJob{ContainerName: "db", Name: "backup", Task: "pg_dump", LockGroup: "disk"})
+
+	release, err := w.locks.acquire("disk", "db/vacuum", LockPolicyWait, 0, w.shutdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const held = 100 * time.Millisecond
+	time.AfterFunc(held, release)
+
+	w.runChain(backup, newJobRun(time.Now()))
+
+	tree, _ := recordedSpans(t, exporter)
+	jobSpan := tree.get(t, "job db/backup")
+	assertChild(t, tree.get(t, "run db/backup"), jobSpan)
+
+	wait := tree.get(t, "lock.wait")
+	assertChild(t, jobSpan, wait)
+	if d := wait.EndTime.Sub(wait.StartTime); d < held/2 {
+		t.Errorf("span %q lasted %v, want about %v", wait.Name, d, held)
+	}
+
+	exec := tree.get(t, "docker.exec_create")
+	assertChild(t, jobSpan, exec)
+	if exec.StartTime.Before(wait.EndTime) {
+		t.Errorf("exec created before the lock was acquired")
+	}
+	if jobSpan.Status.Code != codes.Unset {
+		t.Errorf("span %q status = %v, want unset", jobSpan.Name, jobSpan.Status.Code)
+	}
+}
+
+func TestTraceLockTimeout(t *testing.T) {
+	w, exporter := newTracedWorker(t)
+
+	timeout := 50 * time.Millisecond
+	backup := addTestJob(t, w, types.CronJob{ContainerName: "db", Name: "backup", Task: "pg_dump", LockGroup: "disk", LockTimeout: &timeout})
+
+	release, err := w.locks.acquire("disk", "db/vacuum", LockPolicyWait, 0, w.shutdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	w.runChain(backup, newJobRun(time.Now()))
+
+	tree, _ := recordedSpans(t, exporter)
+	jobSpan := tree.get(t, "job db/backup")
+	if jobSpan.Status.Code != codes.Error || !strings.Contains(jobSpan.Status.Description, "timed out") {
+		t.Errorf("span %q status = %v %q, want a lock timeout error", jobSpan.Name, jobSpan.Status.Code, jobSpan.Status.Description)
+	}
+	if _, ok := tree["docker.exec_create"]; ok {
+		t.Error("job without the lock was executed")
+	}
+}
@@ -3,10 +3,17 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/amir-mohammad-HP/crontask/internal/job"
+	"github.com/amir-mohammad-HP/crontask/internal/metrics"
+	"github.com/amir-mohammad-HP/crontask/internal/schedule"
+	"github.com/amir-mohammad-HP/crontask/internal/store"
 	"github.com/amir-mohammad-HP/crontask/internal/types"
 	"github.com/amir-mohammad-HP/crontask/pkg/docker"
 	"github.com/amir-mohammad-HP/crontask/pkg/logger"
@@ -21,17 +28,62 @@ type Worker struct {
 	cron        *cron.Cron
 	jobRegistry *job.JobRegistry
 	dockerMon   *docker.DockerMonitor
+	blackouts   []*schedule.Window
+	startedJobs map[string]bool
+	locks       *lockGroups
+	store       *store.Store
+	interrupted map[string]store.RunRecord // Interrupted runs by job key, waiting for their job
+	paused      map[string]bool            // Paused jobs by job key
+	startedAt   time.Time
+	health      *health
+	runsMu      sync.Mutex     // Orders new runs against closing shutdown
+	runs        sync.WaitGroup // Runs in flight, awaited before the store closes
 }
 
+// errJobSkipped is returned for runs that were skipped rather than executed
+var errJobSkipped = errors.New("job skipped")
+
 // Worker constructor 😑 why the hell you guys make this lang unreadable
 func New(cfg *types.Config, logger *logger.StdLogger) *Worker {
+	location, err := schedule.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		logger.Error("Failed to load scheduler time zone, falling back to local | %s", err.Error())
+		location = time.Local
+	}
+
 	w := &Worker{
-		config:   cfg,
-		logger:   logger,
-		shutdown: make(chan struct{}),
-		cron: cron.New(cron.WithParser(cron.NewParser(
-			cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
-		))),
+		config:      cfg,
+		logger:      logger,
+		shutdown:    make(chan struct{}),
+		startedJobs: make(map[string]bool),
+		interrupted: make(map[string]store.RunRecord),
+		paused:      make(map[string]bool),
+		health:      newHealth(),
+		locks:       newLockGroups(),
+		cron: cron.New(
+			cron.WithParser(schedule.Parser),
+			cron.WithLocation(location),
+		),
+	}
+
+	for _, spec := range cfg.Scheduler.Blackouts {
+		window, err := schedule.ParseWindow(spec)
+		if err != nil {
+			logger.Error("Ignoring blackout window | %s", err.Error())
+			continue
+		}
+		w.blackouts = append(w.blackouts, window)
+	}
+
+	// Open the history store if enabled
+	if cfg.Store.Enabled {
+		st, err := store.Open(&cfg.Store)
+		if err != nil {
+			logger.Error("Failed to open history store, runs will not be recorded | %s", err.Error())
+		} else {
+			logger.Info("History store opened | %s: %s", "path", st.Path())
+			w.store = st
+		}
 	}
 
 	// Initialize Docker monitor if enabled
@@ -50,22 +102,53 @@ func New(cfg *types.Config, logger *logger.StdLogger) *Worker {
 
 func (w *Worker) Start(ctx context.Context, wg *sync.WaitGroup) error {
 	w.logger.Debug("worker | starting worker")
+	w.startedAt = time.Now()
+	if w.config.Worker.DryRun {
+		w.logger.Warn("worker | dry run, jobs are scheduled and recorded but never executed")
+	}
 
 	go w.runCron(ctx, wg)
-	// go w.runDockerMon(ctx, wg)
+	go w.runDockerMon(ctx, wg)
 
 	return nil
 }
 
 func (w *Worker) Stop() error {
-	w.Cleanup()
+	w.runsMu.Lock()
 	close(w.shutdown)
+	w.runsMu.Unlock()
+
+	// Runs still in flight record their outcome before the store closes
+	w.logger.Debug("worker | waiting for running jobs")
+	<-w.cron.Stop().Done()
+	w.runs.Wait()
+
+	w.Cleanup()
 	w.logger.Debug("worker | stopped")
 	return nil
 }
 
+// trackRun counts a run as in flight so Stop waits for it, false once the
+// worker is stopping. Every true return must be paired with w.runs.Done.
+func (w *Worker) trackRun() bool {
+	w.runsMu.Lock()
+	defer w.runsMu.Unlock()
+
+	select {
+	case <-w.shutdown:
+		return false
+	default:
+	}
+
+	w.runs.Add(1)
+	return true
+}
+
 func (w *Worker) Cleanup() error {
 	w.logger.Debug("worker | cleanup")
+	if w.store != nil {
+		return w.store.Close()
+	}
 	return nil
 }
 
@@ -75,10 +158,24 @@ func (w *Worker) handleDockerEvents(ctx context.Context) {
 	}
 
 	events := w.dockerMon.GetEvents()
+	lifecycleEvents := w.dockerMon.GetLifecycleEvents()
+
+	// Ticks prove the loop is not wedged on an event
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	w.health.beat(&w.health.eventBeat)
+
 	for {
+		metrics.QueueDepth.WithLabelValues("container_events").Set(float64(len(events)))
+		metrics.QueueDepth.WithLabelValues("lifecycle_events").Set(float64(len(lifecycleEvents)))
+
 		select {
 		case event := <-events:
 			w.processDockerEvent(event)
+		case event := <-lifecycleEvents:
+			w.processLifecycleEvent(event)
+		case <-heartbeat.C:
+			w.health.beat(&w.health.eventBeat)
 		case <-ctx.Done():
 			return
 		case <-w.shutdown:
@@ -92,88 +189,313 @@ func (w *Worker) processDockerEvent(event docker.ContainerEvent) {
 	case "scan", "create", "start", "update":
 		w.logger.Debug("Docker change state : %s", event.Action)
 		if event.Container.State == "running" {
-			w.registerContainerJobs(event.Container)
+			w.registerContainerJobs(event.Container, event.Action)
 		}
 	case "die", "destroy":
 		w.logger.Debug("Docker death state : %s", event.Action)
-		w.unregisterContainerJobs(event.ContainerID)
+		w.forgetRemovedJobs(w.unregisterContainerJobs(event.ContainerID)...)
+		if event.Action == "destroy" {
+			w.forgetContainerStarts(event.ContainerID)
+		}
+	}
+}
+
+// processLifecycleEvent hands a raw container event to matching event jobs
+func (w *Worker) processLifecycleEvent(event docker.LifecycleEvent) {
+	if w.jobRegistry == nil {
+		return
+	}
+
+	for _, dockerJob := range w.jobRegistry.GetAllJobs() {
+		if dockerJob.Trigger() != types.TriggerEvent || !dockerJob.MatchesEvent(event) {
+			continue
+		}
+
+		w.logger.Debug("Event matched job | %s: %s, %s: %s, %s: %s",
+			"job", dockerJob.Name(),
+			"event", event.Action,
+			"source", event.Name)
+
+		dockerJob.QueueEvent(event, func(event docker.LifecycleEvent) {
+			w.fireEventJob(dockerJob, event)
+		})
 	}
 }
 
-func (w *Worker) registerContainerJobs(container *docker.ContainerInfo) {
+// fireEventJob runs an event job once its debounce has settled
+func (w *Worker) fireEventJob(dockerJob *job.DockerJob, event docker.LifecycleEvent) {
+	// The job's own container may have gone away in the meantime
+	if current, ok := w.jobRegistry.GetJob(dockerJob.ID()); !ok || current != dockerJob {
+		return
+	}
+
+	run := newJobRun(time.Now().In(w.jobLocation(dockerJob)))
+	if !dockerJob.AllowEventRun(run.scheduled) {
+		w.skipJob(dockerJob, run, "event rate limit reached")
+		return
+	}
+
+	w.logger.Info("Event triggered job | %s: %s, %s: %s, %s: %s, %s: %s",
+		"job", dockerJob.Name(),
+		"run", run.id,
+		"event", event.Action,
+		"source", event.Name)
+
+	w.dispatchRun(dockerJob, run)
+}
+
+func (w *Worker) registerContainerJobs(container *docker.ContainerInfo, action string) {
 	if w.dockerMon == nil || w.jobRegistry == nil {
 		return
 	}
 
-	// Remove existing jobs for this container first
-	w.unregisterContainerJobs(container.ID)
+	// Remove existing jobs for this container first, the series of those
+	// not registered again are dropped at the end
+	removed := w.unregisterContainerJobs(container.ID)
+	defer w.forgetRemovedJobs(removed...)
 
 	// Extract and register new jobs
 	cronJobs := w.dockerMon.ExtractCronJobs(container)
 	for _, cronJob := range cronJobs {
-		dockerJob := job.NewDockerJob(
-			cronJob.ContainerID,
-			cronJob.ContainerName,
-			cronJob.CronExpr,
-			cronJob.Task,
-			w.dockerMon,
-		)
+		if cronJob.Trigger == types.TriggerAt && !cronJob.At.After(time.Now()) {
+			w.logger.Debug("One-shot job time has passed | %s: %s, %s: %s, %s: %s",
+				"container", container.ID[:12],
+				"job", cronJob.Name,
+				"at", cronJob.At.Format(time.RFC3339))
+			if action == "scan" {
+				w.catchUp(job.NewDockerJob(cronJob, w.dockerMon))
+			}
+			continue
+		}
+
+		dockerJob := job.NewDockerJob(cronJob, w.dockerMon)
 
 		// Add to registry
 		if w.jobRegistry.AddJob(dockerJob) {
-			// Schedule the job
-			entryID, err := w.cron.AddFunc(cronJob.CronExpr, func() {
-				w.executeJob(dockerJob)
-			})
-
-			if err != nil {
+			if err := w.scheduleJob(dockerJob, action); err != nil {
 				w.logger.Error("Failed to schedule job | %s, %s: %s , %s: %s",
 					err.Error(),
 					"container", container.ID[:12],
-					"cron", cronJob.CronExpr)
-				w.jobRegistry.RemoveJob(dockerJob.Name())
-			} else {
-				dockerJob.SetCronEntryID(entryID)
-				w.logger.Info("Job registered | %s: %s, %s: %s, %s: %s, %s: %s",
-					"container", container.ID[:12],
-					"name", container.Name,
-					"cron", cronJob.CronExpr,
-					"task", cronJob.Task)
+					"job", dockerJob.Name())
+				w.jobRegistry.RemoveJob(dockerJob.ID())
+				continue
 			}
+
+			if !w.checkDependencies(dockerJob) {
+				w.removeJob(dockerJob)
+				continue
+			}
+
+			w.restoreJobState(dockerJob)
+			if action == "scan" {
+				w.catchUp(dockerJob)
+			}
+			w.rerunInterrupted(dockerJob)
+
+			w.logger.Info("Job registered | %s: %s, %s: %s, %s: %s, %s: %s, %s: %s, %s: %s, %s: %s",
+				"container", container.ID[:12],
+				"name", container.Name,
+				"trigger", dockerJob.Trigger(),
+				"cron", dockerJob.Schedule(),
+				"schedule", dockerJob.Describe(),
+				"timezone", w.jobLocation(dockerJob).String(),
+				"task", cronJob.Task)
 		}
 	}
+
+	w.updateRegistryMetrics()
 }
 
-func (w *Worker) unregisterContainerJobs(containerID string) {
-	if w.jobRegistry == nil {
+// scheduleJob hooks a registered job up to its trigger
+func (w *Worker) scheduleJob(dockerJob *job.DockerJob, action string) error {
+	switch dockerJob.Trigger() {
+	case types.TriggerAt:
+		entryID := w.cron.Schedule(schedule.Once(dockerJob.At()), cron.FuncJob(func() {
+			w.dispatchScheduled(dockerJob, dockerJob.At())
+			w.removeJob(dockerJob)
+		}))
+		dockerJob.SetCronEntryID(entryID)
+
+	case types.TriggerStart:
+		if action == "start" {
+			w.triggerOnStart(dockerJob)
+		}
+
+	case types.TriggerChain:
+		// Runs when its upstream jobs do
+
+	default:
+		sched, err := schedule.Parse(dockerJob.Schedule())
+		if err != nil {
+			return fmt.Errorf("invalid cron expression %q: %w", dockerJob.Schedule(), err)
+		}
+
+		entryID := w.cron.Schedule(sched, cron.FuncJob(func() {
+			// The entry's previous fire time is the time this run was due
+			w.dispatchScheduled(dockerJob, w.cron.Entry(dockerJob.GetCronEntryID()).Prev)
+		}))
+		dockerJob.SetCronEntryID(entryID)
+		dockerJob.UpdateNextRun(sched, w.cron.Location())
+	}
+
+	return nil
+}
+
+// triggerOnStart runs a start-triggered job after its delay
+func (w *Worker) triggerOnStart(dockerJob *job.DockerJob) {
+	if dockerJob.StartOnce() {
+		w.mu.Lock()
+		started := w.startedJobs[dockerJob.ID()]
+		w.startedJobs[dockerJob.ID()] = true
+		w.mu.Unlock()
+
+		if started {
+			w.logger.Debug("Start job already ran for this container | %s: %s",
+				"job", dockerJob.Name())
+			return
+		}
+	}
+
+	go func() {
+		if delay := dockerJob.StartDelay(); delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+			case <-w.shutdown:
+				return
+			}
+		}
+
+		// The container may have stopped or been updated while waiting
+		if current, ok := w.jobRegistry.GetJob(dockerJob.ID()); !ok || current != dockerJob {
+			return
+		}
+
+		w.dispatchJob(dockerJob)
+	}()
+}
+
+// forgetContainerStarts clears the start-once bookkeeping of a destroyed container
+func (w *Worker) forgetContainerStarts(containerID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for id := range w.startedJobs {
+		if strings.HasPrefix(id, containerID[:12]+"-") {
+			delete(w.startedJobs, id)
+		}
+	}
+}
+
+// removeJob drops a single job and its cron entry
+func (w *Worker) removeJob(dockerJob *job.DockerJob) {
+	if current, ok := w.jobRegistry.GetJob(dockerJob.ID()); !ok || current != dockerJob {
 		return
 	}
 
+	w.jobRegistry.RemoveJob(dockerJob.ID())
+	dockerJob.Stop()
+	if entryID := dockerJob.GetCronEntryID(); entryID != 0 {
+		w.cron.Remove(entryID)
+	}
+	w.forgetRemovedJobs(dockerJob)
+
+	w.logger.Info("Job removed | %s: %s, %s: %s",
+		"container", dockerJob.GetContainerID()[:12],
+		"job", dockerJob.Name())
+	w.updateRegistryMetrics()
+}
+
+// unregisterContainerJobs drops a container's jobs and returns them
+func (w *Worker) unregisterContainerJobs(containerID string) []*job.DockerJob {
+	if w.jobRegistry == nil {
+		return nil
+	}
+
 	removedJobs := w.jobRegistry.RemoveJobsByContainer(containerID)
-	for _, jobID := range removedJobs {
-		// Note: cron entries are automatically removed when container stops
+	for _, removed := range removedJobs {
+		removed.Stop()
+		if entryID := removed.GetCronEntryID(); entryID != 0 {
+			w.cron.Remove(entryID)
+		}
+
 		w.logger.Info("Job unregistered | %s: %s, %s: %s",
 			"container", containerID[:12],
-			"job", jobID)
+			"job", removed.Name())
 	}
+
+	w.updateRegistryMetrics()
+	return removedJobs
 }
 
-func (w *Worker) executeJob(job *job.DockerJob) {
-	w.logger.Info("Executing job | %s: %s,  %s: %s,  %s: %s",
-		"job", job.Name(),
-		"container", job.GetContainerID()[:12],
-		"time", time.Now().Format("2006-01-02 15:04:05"))
+// dispatchJob starts a run of the job due now
+func (w *Worker) dispatchJob(job *job.DockerJob) {
+	w.dispatchScheduled(job, time.Now())
+}
 
-	if err := job.Execute(); err != nil {
-		w.logger.Error("Job execution failed | %s, %s: %s, %s: %s",
-			err.Error(),
-			"job", job.Name(),
-			"container", job.GetContainerID()[:12])
-	} else {
-		w.logger.Info("Job executed successfully | %s: %s, %s: %s",
+// dispatchScheduled starts a run of the job that was due at scheduled
+func (w *Worker) dispatchScheduled(job *job.DockerJob, scheduled time.Time) {
+	if scheduled.IsZero() {
+		scheduled = time.Now()
+	}
+	w.dispatchRun(job, newJobRun(scheduled.In(w.jobLocation(job))))
+}
+
+// dispatchRun checks the job's time windows and waits out its random
+// jitter before running it
+func (w *Worker) dispatchRun(job *job.DockerJob, run *jobRun) {
+	if !w.trackRun() {
+		return
+	}
+	defer w.runs.Done()
+
+	if w.isPaused(job) {
+		w.skipJob(job, run, "job is paused")
+		return
+	}
+
+	if reason := w.suppressed(job, run.scheduled); reason != "" {
+		w.skipJob(job, run, reason)
+		return
+	}
+
+	if jitter := job.Jitter(); jitter > 0 {
+		delay := rand.N(jitter)
+		w.logger.Debug("Delaying job | %s: %s, %s: %s, %s: %s",
 			"job", job.Name(),
-			"container", job.GetContainerID()[:12])
+			"run", run.id,
+			"delay", delay.String())
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-w.shutdown:
+			return
+		}
+
+		// The delay may have carried the run past the end of a window
+		if reason := w.suppressed(job, time.Now().In(w.jobLocation(job))); reason != "" {
+			w.skipJob(job, run, reason+" after jitter")
+			return
+		}
 	}
+
+	w.runChain(job, run)
+}
+
+// suppressed returns why a run at t must be skipped, empty when it may run
+func (w *Worker) suppressed(job *job.DockerJob, t time.Time) string {
+	for _, window := range w.blackouts {
+		if window.Contains(t.In(w.cron.Location())) {
+			return fmt.Sprintf("inside blackout window %q", window.String())
+		}
+	}
+
+	return job.Suppressed(t)
 }
 
 // GetStats returns worker statistics
@@ -181,14 +503,22 @@ func (w *Worker) GetStats() map[string]interface{} {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	// The health heartbeat is not a job
+	entries := len(w.cron.Entries())
+	if w.cron.Entry(w.health.heartbeatEntry()).Valid() {
+		entries--
+	}
+
 	stats := map[string]interface{}{
-		"cron_entries": len(w.cron.Entries()),
+		"cron_entries": entries,
 	}
 
 	if w.jobRegistry != nil {
 		stats["registered_jobs"] = w.jobRegistry.Count()
 	}
 
+	stats["lock_groups"] = w.locks.snapshot()
+
 	return stats
 }
 
@@ -202,14 +532,62 @@ func (w *Worker) ListJobs() []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, len(jobs))
 
 	for _, job := range jobs {
-		result = append(result, map[string]interface{}{
-			"id":           job.Name(),
-			"container_id": job.GetContainerID()[:12],
-			"cron_expr":    job.Schedule(),
-			"last_run":     job.GetLastRun(),
-			"next_run":     job.GetNextRun(),
-		})
+		result = append(result, w.jobInfo(job))
 	}
 
 	return result
 }
+
+// jobInfo describes a registered job and its run times
+func (w *Worker) jobInfo(job *job.DockerJob) map[string]interface{} {
+	location := w.jobLocation(job)
+
+	var lastRun string
+	if last := job.GetLastRun(); last != nil {
+		lastRun = last.In(location).Format(time.RFC3339)
+	}
+
+	next := job.GetNextRun()
+	if entry := w.cron.Entry(job.GetCronEntryID()); entry.Valid() && !entry.Next.IsZero() {
+		next = entry.Next
+	}
+
+	var nextRun string
+	if !next.IsZero() {
+		nextRun = next.In(location).Format(time.RFC3339)
+	}
+
+	var lastSkipped string
+	lastSkip, skipReason := job.GetLastSkip()
+	if lastSkip != nil {
+		lastSkipped = lastSkip.In(location).Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"id":           job.Name(),
+		"key":          job.Key(),
+		"name":         job.JobName(),
+		"container_id": job.GetContainerID()[:12],
+		"container":    job.GetContainerName(),
+		"trigger":      job.Trigger(),
+		"cron_expr":    job.Schedule(),
+		"expression":   job.Expression(),
+		"description":  job.Describe(),
+		"timezone":     location.String(),
+		"last_run":     lastRun,
+		"next_run":     nextRun,
+		"last_skipped": lastSkipped,
+		"skip_reason":  skipReason,
+		"paused":       w.isPaused(job),
+	}
+}
+
+// jobLocation returns the zone a job's schedule is evaluated in
+func (w *Worker) jobLocation(job *job.DockerJob) *time.Location {
+	if tz := job.Timezone(); tz != "" {
+		if location, err := schedule.LoadLocation(tz); err == nil {
+			return location
+		}
+	}
+	return w.cron.Location()
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/store"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+func TestStopWaitsForRuns(t *testing.T) {
+	storeConfig := types.StoreConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "crontask.db")}
+	w := newTestWorker(t, func(cfg *types.Config) {
+		cfg.Store = storeConfig
+	})
+
+	backup := addTestJob(t, w, types.CronJob{ContainerName: "db", Name: "backup", Task: "sleep 1", Trigger: types.TriggerCron})
+	go w.dispatchJob(backup)
+
+	// Stop while the exec is still running
+	time.Sleep(slowExec / 4)
+	started := time.Now()
+	if err := w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(started); waited < slowExec/2 {
+		t.Errorf("Stop returned after %v, before the run finished", waited)
+	}
+
+	// A run started after Stop is dropped rather than hitting a closed store
+	w.dispatchJob(backup)
+
+	st, err := store.Open(&storeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	unfinished, err := st.Unfinished()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unfinished) != 0 {
+		t.Errorf("unfinished runs = %d, want 0 so the next start does not treat them as interrupted", len(unfinished))
+	}
+
+	history, err := st.History(store.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Outcome != store.OutcomeSuccess {
+		t.Fatalf("history = %+v, want one successful run", history)
+	}
+}
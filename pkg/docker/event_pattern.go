@@ -0,0 +1,145 @@
+// pkg/docker/event_pattern.go
+package docker
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LifecycleEvent is a raw container event, used to trigger event jobs
+type LifecycleEvent struct {
+	Type        string
+	Action      string
+	ContainerID string
+	Name        string
+	Image       string
+	Attributes  map[string]string
+	Time        time.Time
+}
+
+// EventPattern selects lifecycle events by type, action and container.
+//
+// The trigger is written as <type>:<action>, where the action may use glob
+// wildcards and Docker's "health_status: healthy" is written without the
+// space ("container:health_status:healthy", "container:die"). The selector is
+// a comma-separated list of key=glob terms that must all match, with keys
+// name, image, service, project and label.<key>.
+type EventPattern struct {
+	Type     string
+	Action   string
+	Selector map[string]string
+}
+
+// Supported event types
+var eventTypes = map[string]bool{
+	"container": true,
+}
+
+// ParseEventPattern parses an on_event trigger and its container selector
+func ParseEventPattern(trigger string, selector string) (*EventPattern, error) {
+	eventType, action, found := strings.Cut(strings.TrimSpace(trigger), ":")
+	if !found || eventType == "" || action == "" {
+		return nil, fmt.Errorf("invalid event trigger %q: expected <type>:<action>", trigger)
+	}
+
+	if !eventTypes[eventType] {
+		return nil, fmt.Errorf("unsupported event type %q", eventType)
+	}
+
+	if _, err := path.Match(action, ""); err != nil {
+		return nil, fmt.Errorf("invalid event action %q: %w", action, err)
+	}
+
+	pattern := &EventPattern{
+		Type:     eventType,
+		Action:   normalizeAction(action),
+		Selector: make(map[string]string),
+	}
+
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(term, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid selector term %q: expected key=value", term)
+		}
+
+		switch {
+		case key == "name", key == "image", key == "service", key == "project":
+		case strings.HasPrefix(key, "label.") && len(key) > len("label."):
+		default:
+			return nil, fmt.Errorf("unsupported selector key %q", key)
+		}
+
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("invalid selector value %q: %w", value, err)
+		}
+		pattern.Selector[key] = value
+	}
+
+	return pattern, nil
+}
+
+// Matches reports whether the event fits the pattern
+func (p *EventPattern) Matches(event LifecycleEvent) bool {
+	if event.Type != p.Type {
+		return false
+	}
+
+	if ok, _ := path.Match(p.Action, normalizeAction(event.Action)); !ok {
+		return false
+	}
+
+	for key, want := range p.Selector {
+		var got string
+		switch key {
+		case "name":
+			got = event.Name
+		case "image":
+			got = event.Image
+		case "service":
+			got = event.Attributes[ComposeServiceLabel]
+		case "project":
+			got = event.Attributes[ComposeProjectLabel]
+		default:
+			got = event.Attributes[strings.TrimPrefix(key, "label.")]
+		}
+
+		if ok, _ := path.Match(want, got); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Docker reports health changes as "health_status: healthy"
+func normalizeAction(action string) string {
+	return strings.ReplaceAll(action, " ", "")
+}
+
+// ParseRateLimit parses "<count>/<duration>", e.g. "3/10m"
+func ParseRateLimit(spec string) (int, time.Duration, error) {
+	count, per, found := strings.Cut(strings.TrimSpace(spec), "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid rate %q: expected <count>/<duration>", spec)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return 0, 0, fmt.Errorf("invalid rate count %q", count)
+	}
+
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return 0, 0, fmt.Errorf("invalid rate period %q", per)
+	}
+
+	return n, d, nil
+}
@@ -0,0 +1,192 @@
+// pkg/docker/exec_options.go
+package docker
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+// Named job labels setting an environment variable look like
+// prefix.job.<name>.env.<VAR>=value
+const envLabelPrefix = "env."
+
+// user, uid, user:group or uid:gid
+var execUserPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*(:[A-Za-z0-9_][A-Za-z0-9_.-]*)?$`)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Shells a task string may be run with, besides absolute paths
+var shells = map[string]bool{"sh": true, "bash": true, "ash": true}
+
+// DefaultShell runs task strings when no shell is configured
+const DefaultShell = "sh"
+
+// Command returns the arguments a task is executed with. A task that is a
+// JSON array of strings is used as is, in exec form, so images without a
+// shell work. Any other task, including shell tests like [ -f file ], is a
+// string run with shell -c.
+func Command(task string, shell string) ([]string, error) {
+	if args, ok := execArgs(task); ok {
+		if len(args) == 0 || args[0] == "" {
+			return nil, fmt.Errorf("invalid exec form command: missing executable")
+		}
+		return args, nil
+	}
+
+	if shell == "" {
+		shell = DefaultShell
+	}
+	return []string{shell, "-c", task}, nil
+}
+
+// ValidateExecOptions checks exec settings from the config or labels
+func ValidateExecOptions(options types.ExecOptions) error {
+	if options.Shell != "" && !shells[options.Shell] && !path.IsAbs(options.Shell) {
+		return fmt.Errorf("invalid shell %q, expected sh, bash, ash or an absolute path", options.Shell)
+	}
+
+	if options.User != "" && !execUserPattern.MatchString(options.User) {
+		return fmt.Errorf("invalid user %q, expected user, uid, user:group or uid:gid", options.User)
+	}
+
+	switch options.Template {
+	case "", TemplateOff, TemplateOn, TemplateStrict:
+	default:
+		return fmt.Errorf("invalid template mode %q, expected off, on or strict", options.Template)
+	}
+
+	if options.WorkDir != "" && !path.IsAbs(options.WorkDir) {
+		return fmt.Errorf("invalid workdir %q, expected an absolute path", options.WorkDir)
+	}
+
+	for _, env := range options.Env {
+		name, _, found := strings.Cut(env, "=")
+		if !found || !envNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid environment variable %q, expected KEY=value", env)
+		}
+	}
+
+	return nil
+}
+
+// DefaultExecOptions returns the configured exec settings every job starts from
+func (dm *DockerMonitor) DefaultExecOptions() types.ExecOptions {
+	options := dm.config.Exec
+	options.Env = append([]string(nil), dm.config.Exec.Env...)
+	return options
+}
+
+// Apply a named job's user, shell, template, workdir, env.<VAR>, privileged
+// and tty labels over the configured defaults
+func (dm *DockerMonitor) parseExecOptions(name string, values map[string]string) (types.ExecOptions, error) {
+	options := dm.DefaultExecOptions()
+
+	if user := strings.TrimSpace(values["user"]); user != "" {
+		if err := ValidateExecOptions(types.ExecOptions{User: user}); err != nil {
+			return types.ExecOptions{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "user"), err)
+		}
+		options.User = user
+	}
+	if shell := strings.TrimSpace(values["shell"]); shell != "" {
+		if err := ValidateExecOptions(types.ExecOptions{Shell: shell}); err != nil {
+			return types.ExecOptions{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "shell"), err)
+		}
+		options.Shell = shell
+	}
+	if mode := strings.TrimSpace(values["template"]); mode != "" {
+		if err := ValidateExecOptions(types.ExecOptions{Template: mode}); err != nil {
+			return types.ExecOptions{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "template"), err)
+		}
+		options.Template = mode
+	}
+	if workDir := strings.TrimSpace(values["workdir"]); workDir != "" {
+		if err := ValidateExecOptions(types.ExecOptions{WorkDir: workDir}); err != nil {
+			return types.ExecOptions{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "workdir"), err)
+		}
+		options.WorkDir = workDir
+	}
+
+	// Label variables replace configured ones of the same name
+	var names []string
+	for key := range values {
+		if envName, ok := strings.CutPrefix(key, envLabelPrefix); ok {
+			names = append(names, envName)
+		}
+	}
+	sort.Strings(names)
+	for _, envName := range names {
+		options.Env = setEnv(options.Env, envName, values[envLabelPrefix+envName])
+	}
+
+	for _, key := range []string{"privileged", "tty"} {
+		value := strings.TrimSpace(values[key])
+		if value == "" {
+			continue
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return types.ExecOptions{}, fmt.Errorf("label %q: invalid boolean %q", dm.jobLabel(name, key), value)
+		}
+		if key == "privileged" {
+			options.Privileged = b
+		} else {
+			options.Tty = b
+		}
+	}
+
+	if options.Privileged && !dm.config.AllowPrivileged {
+		return types.ExecOptions{}, fmt.Errorf("label %q: privileged execs are disabled, set docker.allow_privileged",
+			dm.jobLabel(name, "privileged"))
+	}
+
+	return options, nil
+}
+
+// checkTask validates a job's task template against the container it runs
+// in, with placeholders for the run
+func (dm *DockerMonitor) checkTask(container *ContainerInfo, cronJob *types.CronJob) error {
+	return checkTaskTemplate(cronJob.Task, cronJob.Exec.Template, TaskData{
+		JobID:         JobID(container.ID, cronJob.Name),
+		JobName:       cronJob.Name,
+		JobKey:        container.Name + "/" + cronJob.Name,
+		Trigger:       cronJob.Trigger,
+		ContainerID:   container.ID,
+		ContainerName: container.Name,
+		Service:       cronJob.Service,
+		Image:         container.Image,
+		Labels:        container.Labels,
+		RunID:         "0000000000000000",
+		Scheduled:     time.Now(),
+		Attempt:       1,
+	})
+}
+
+// MergeEnv returns env with the KEY=value entries of overrides set, replacing
+// entries of the same name. env is not modified.
+func MergeEnv(env []string, overrides []string) []string {
+	merged := append([]string(nil), env...)
+	for _, entry := range overrides {
+		name, value, _ := strings.Cut(entry, "=")
+		merged = setEnv(merged, name, value)
+	}
+	return merged
+}
+
+// setEnv sets name in a KEY=value list, replacing an existing entry
+func setEnv(env []string, name string, value string) []string {
+	for i, entry := range env {
+		if key, _, _ := strings.Cut(entry, "="); key == name {
+			env[i] = name + "=" + value
+			return env
+		}
+	}
+	return append(env, name+"="+value)
+}
@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		task  string
+		shell string
+		want  []string
+		err   bool
+	}{
+		{task: "pg_dump app", want: []string{"sh", "-c", "pg_dump app"}},
+		{task: "pg_dump app", shell: "bash", want: []string{"bash", "-c", "pg_dump app"}},
+		{task: `["/app/cleanup", "--older-than", "7d"]`, shell: "bash", want: []string{"/app/cleanup", "--older-than", "7d"}},
+		{task: ` ["/app/cleanup"] `, want: []string{"/app/cleanup"}},
+		{task: "[ -f /tmp/lock ] && rm /tmp/lock", want: []string{"sh", "-c", "[ -f /tmp/lock ] && rm /tmp/lock"}},
+		{task: "[[ -d /data ]] && du -sh /data", shell: "bash", want: []string{"bash", "-c", "[[ -d /data ]] && du -sh /data"}},
+		{task: `["/app/cleanup", 7]`, want: []string{"sh", "-c", `["/app/cleanup", 7]`}},
+		{task: "[]", err: true},
+		{task: `[""]`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			got, err := Command(tt.task, tt.shell)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("Command(%q) = %q, want an error", tt.task, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Command(%q) = %v", tt.task, err)
+			}
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("Command(%q) = %q, want %q", tt.task, got, tt.want)
+			}
+		})
+	}
+}
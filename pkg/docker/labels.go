@@ -0,0 +1,560 @@
+// pkg/docker/labels.go
+package docker
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir-mohammad-HP/crontask/internal/schedule"
+	"github.com/amir-mohammad-HP/crontask/internal/types"
+)
+
+// Named job labels look like prefix.job.<name>.<key>=value
+const jobLabelSegment = "job."
+
+// Labels Docker Compose sets on the containers it manages
+const (
+	ComposeServiceLabel = "com.docker.compose.service"
+	ComposeProjectLabel = "com.docker.compose.project"
+)
+
+// JobID identifies a container's job, as listed by the API and passed to
+// task templates
+func JobID(containerID string, name string) string {
+	return fmt.Sprintf("docker-%s-%s", containerID[:12], name)
+}
+
+// Keys accepted on named job labels
+var jobLabelKeys = map[string]bool{
+	"schedule":         true,
+	"at":               true,
+	"on_start":         true,
+	"start_delay":      true,
+	"start_once":       true,
+	"on_event":         true,
+	"event_selector":   true,
+	"event_debounce":   true,
+	"event_rate":       true,
+	"after":            true,
+	"on_success":       true,
+	"on_failure":       true,
+	"lock":             true,
+	"lock_policy":      true,
+	"lock_timeout":     true,
+	"catchup":          true,
+	"catchup_window":   true,
+	"catchup_limit":    true,
+	"interrupt_policy": true,
+	"command":          true,
+	"timezone":         true,
+	"jitter":           true,
+	"allow":            true,
+	"deny":             true,
+	"user":             true,
+	"shell":            true,
+	"template":         true,
+	"workdir":          true,
+	"privileged":       true,
+	"tty":              true,
+}
+
+var jobNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Dependencies name a job, optionally qualified by its container name
+var jobRefPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.-]*/)?[A-Za-z0-9_-]+$`)
+
+// Extract cron jobs from container labels
+func (dm *DockerMonitor) ExtractCronJobs(container *ContainerInfo) []types.CronJob {
+	cronJobs, errs := dm.ParseCronJobs(container)
+	for _, err := range errs {
+		dm.logger.Warn("Failed to parse cron job label | %s", err.Error())
+	}
+
+	return cronJobs
+}
+
+// ParseCronJobs extracts cron jobs from container labels and returns every
+// label that could not be turned into a job alongside the valid ones.
+//
+// Two label forms are supported:
+//
+//	prefix.cronjob('<expr>').task=<task>
+//	prefix.job.<name>.schedule=<expr>
+//	prefix.job.<name>.at=<RFC 3339 time>
+//	prefix.job.<name>.on_start=true
+//	prefix.job.<name>.start_delay=<duration>
+//	prefix.job.<name>.start_once=true
+//	prefix.job.<name>.on_event=<type>:<action>
+//	prefix.job.<name>.event_selector=<key>=<glob>[,...]
+//	prefix.job.<name>.event_debounce=<duration>
+//	prefix.job.<name>.event_rate=<count>/<duration>
+//	prefix.job.<name>.after=<job>[,...]
+//	prefix.job.<name>.on_success=<job>[,...]
+//	prefix.job.<name>.on_failure=<job>[,...]
+//	prefix.job.<name>.lock=<group>
+//	prefix.job.<name>.lock_policy=wait|skip
+//	prefix.job.<name>.lock_timeout=<duration>
+//	prefix.job.<name>.catchup=ignore|once|all
+//	prefix.job.<name>.catchup_window=<duration>
+//	prefix.job.<name>.catchup_limit=<count>
+//	prefix.job.<name>.interrupt_policy=ignore|rerun
+//	prefix.job.<name>.command=<task>|<JSON array of arguments>
+//	prefix.job.<name>.timezone=<zone>
+//	prefix.job.<name>.jitter=<duration>
+//	prefix.job.<name>.allow=<window>[;<window>...]
+//	prefix.job.<name>.deny=<window>[;<window>...]
+//	prefix.job.<name>.user=<user>[:<group>]
+//	prefix.job.<name>.shell=sh|bash|ash|<absolute path>
+//	prefix.job.<name>.template=off|on|strict
+//	prefix.job.<name>.workdir=<absolute path>
+//	prefix.job.<name>.env.<VAR>=<value>
+//	prefix.job.<name>.privileged=true
+//	prefix.job.<name>.tty=true
+func (dm *DockerMonitor) ParseCronJobs(container *ContainerInfo) ([]types.CronJob, []error) {
+	var cronJobs []types.CronJob
+	var errs []error
+
+	named := make(map[string]map[string]string)
+
+	for labelKey, task := range container.Labels {
+		if !strings.HasPrefix(labelKey, dm.config.LabelPrefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(labelKey, dm.config.LabelPrefix)
+		if strings.HasPrefix(rest, jobLabelSegment) {
+			name, key, err := parseJobLabel(strings.TrimPrefix(rest, jobLabelSegment))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("container %s, label %q: %w",
+					container.Name, labelKey, err))
+				continue
+			}
+
+			if named[name] == nil {
+				named[name] = make(map[string]string)
+			}
+			named[name][key] = task
+			continue
+		}
+
+		cronExpr, err := dm.parseCronExpression(labelKey)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("container %s, label %q: %w",
+				container.Name, labelKey, err))
+			continue
+		}
+
+		cronJob := types.CronJob{
+			ContainerID:   container.ID,
+			ContainerName: container.Name,
+			Service:       container.Labels[ComposeServiceLabel],
+			Image:         container.Image,
+			Labels:        container.Labels,
+			Name:          legacyJobName(labelKey),
+			Trigger:       types.TriggerCron,
+			CronExpr:      cronExpr,
+			Task:          task,
+			Exec:          dm.DefaultExecOptions(),
+			LabelKey:      labelKey,
+			IsActive:      container.State == "running",
+			CreatedAt:     time.Now(),
+		}
+
+		if _, err := Command(task, ""); err != nil {
+			errs = append(errs, fmt.Errorf("container %s, label %q: %w",
+				container.Name, labelKey, err))
+			continue
+		}
+		if err := dm.checkTask(container, &cronJob); err != nil {
+			errs = append(errs, fmt.Errorf("container %s, label %q: %w",
+				container.Name, labelKey, err))
+			continue
+		}
+
+		cronJobs = append(cronJobs, cronJob)
+	}
+
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cronJob, err := dm.buildNamedJob(container, name, named[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("container %s, job %q: %w",
+				container.Name, name, err))
+			continue
+		}
+		cronJobs = append(cronJobs, cronJob)
+	}
+
+	return cronJobs, errs
+}
+
+// Split "<name>.<key>" of a named job label
+func parseJobLabel(rest string) (string, string, error) {
+	name, key, found := strings.Cut(rest, ".")
+	if !found || name == "" || key == "" {
+		return "", "", fmt.Errorf("invalid job label: expected job.<name>.<key>")
+	}
+
+	if !jobNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("invalid job name %q: only letters, digits, '-' and '_' are allowed", name)
+	}
+
+	if envName, ok := strings.CutPrefix(key, envLabelPrefix); ok {
+		if !envNamePattern.MatchString(envName) {
+			return "", "", fmt.Errorf("invalid environment variable name %q", envName)
+		}
+		return name, key, nil
+	}
+
+	if !jobLabelKeys[key] {
+		return "", "", fmt.Errorf("unsupported job key %q", key)
+	}
+
+	return name, key, nil
+}
+
+// Build a cron job from the collected keys of a named job
+func (dm *DockerMonitor) buildNamedJob(container *ContainerInfo, name string, values map[string]string) (types.CronJob, error) {
+	cronJob := types.CronJob{
+		ContainerID:   container.ID,
+		ContainerName: container.Name,
+		Service:       container.Labels[ComposeServiceLabel],
+		Image:         container.Image,
+		Labels:        container.Labels,
+		Name:          name,
+		Task:          values["command"],
+		AllowWindows:  values["allow"],
+		DenyWindows:   values["deny"],
+		IsActive:      container.State == "running",
+		CreatedAt:     time.Now(),
+	}
+
+	if strings.TrimSpace(cronJob.Task) == "" {
+		return types.CronJob{}, fmt.Errorf("missing command")
+	}
+	if _, err := Command(cronJob.Task, ""); err != nil {
+		return types.CronJob{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "command"), err)
+	}
+
+	cronJob.Timezone = strings.TrimSpace(values["timezone"])
+	if cronJob.Timezone != "" {
+		if _, err := schedule.LoadLocation(cronJob.Timezone); err != nil {
+			return types.CronJob{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "timezone"), err)
+		}
+	}
+
+	for _, key := range []string{"after", "on_success", "on_failure"} {
+		for _, ref := range strings.Split(values[key], ",") {
+			if ref = strings.TrimSpace(ref); ref != "" && !jobRefPattern.MatchString(ref) {
+				return types.CronJob{}, fmt.Errorf("label %q: invalid job reference %q",
+					dm.jobLabel(name, key), ref)
+			}
+		}
+	}
+	cronJob.After = values["after"]
+	cronJob.OnSuccess = values["on_success"]
+	cronJob.OnFailure = values["on_failure"]
+
+	if err := dm.parseTrigger(name, values, &cronJob); err != nil {
+		return types.CronJob{}, err
+	}
+
+	var err error
+	if cronJob.Jitter, err = dm.durationLabel(name, "jitter", values); err != nil {
+		return types.CronJob{}, err
+	}
+
+	for _, key := range []string{"allow", "deny"} {
+		if _, err := schedule.ParseWindows(values[key]); err != nil {
+			return types.CronJob{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, key), err)
+		}
+	}
+
+	cronJob.LockGroup = strings.TrimSpace(values["lock"])
+	cronJob.LockPolicy = strings.TrimSpace(values["lock_policy"])
+	if cronJob.LockGroup == "" && (cronJob.LockPolicy != "" || values["lock_timeout"] != "") {
+		return types.CronJob{}, fmt.Errorf("label %q: lock_policy and lock_timeout need a lock group",
+			dm.jobLabel(name, "lock"))
+	}
+	if cronJob.LockGroup != "" && !jobNamePattern.MatchString(cronJob.LockGroup) {
+		return types.CronJob{}, fmt.Errorf("label %q: invalid lock group %q",
+			dm.jobLabel(name, "lock"), cronJob.LockGroup)
+	}
+	if cronJob.LockPolicy != "" && cronJob.LockPolicy != "wait" && cronJob.LockPolicy != "skip" {
+		return types.CronJob{}, fmt.Errorf("label %q: invalid policy %q, expected wait or skip",
+			dm.jobLabel(name, "lock_policy"), cronJob.LockPolicy)
+	}
+	if strings.TrimSpace(values["lock_timeout"]) != "" {
+		timeout, err := dm.durationLabel(name, "lock_timeout", values)
+		if err != nil {
+			return types.CronJob{}, err
+		}
+		cronJob.LockTimeout = &timeout
+	}
+
+	if err := dm.parseCatchUp(name, values, &cronJob); err != nil {
+		return types.CronJob{}, err
+	}
+
+	cronJob.Interrupt = strings.TrimSpace(values["interrupt_policy"])
+	if cronJob.Interrupt != "" && cronJob.Interrupt != types.InterruptIgnore && cronJob.Interrupt != types.InterruptRerun {
+		return types.CronJob{}, fmt.Errorf("label %q: invalid policy %q, expected ignore or rerun",
+			dm.jobLabel(name, "interrupt_policy"), cronJob.Interrupt)
+	}
+
+	if cronJob.Exec, err = dm.parseExecOptions(name, values); err != nil {
+		return types.CronJob{}, err
+	}
+
+	if err := dm.checkTask(container, &cronJob); err != nil {
+		return types.CronJob{}, fmt.Errorf("label %q: %w", dm.jobLabel(name, "command"), err)
+	}
+
+	return cronJob, nil
+}
+
+// Set the trigger of a named job, exactly one of schedule, at, on_start and on_event
+func (dm *DockerMonitor) parseTrigger(name string, values map[string]string, cronJob *types.CronJob) error {
+	onStart, err := dm.boolLabel(name, "on_start", values)
+	if err != nil {
+		return err
+	}
+
+	var triggers []string
+	if strings.TrimSpace(values["schedule"]) != "" {
+		triggers = append(triggers, "schedule")
+	}
+	if strings.TrimSpace(values["at"]) != "" {
+		triggers = append(triggers, "at")
+	}
+	if onStart {
+		triggers = append(triggers, "on_start")
+	}
+	if strings.TrimSpace(values["on_event"]) != "" {
+		triggers = append(triggers, "on_event")
+	}
+
+	// Jobs that follow another job need no trigger of their own
+	if len(triggers) == 0 && cronJob.After+cronJob.OnSuccess+cronJob.OnFailure != "" {
+		triggers = append(triggers, "chain")
+	}
+
+	switch len(triggers) {
+	case 0:
+		return fmt.Errorf("missing trigger: set one of schedule, at, on_start, on_event or a dependency")
+	case 1:
+	default:
+		return fmt.Errorf("conflicting triggers %s: set only one", strings.Join(triggers, ", "))
+	}
+
+	if !onStart {
+		for _, key := range []string{"start_delay", "start_once"} {
+			if values[key] != "" {
+				return fmt.Errorf("label %q: only valid with on_start", dm.jobLabel(name, key))
+			}
+		}
+	}
+
+	if triggers[0] != "on_event" {
+		for _, key := range []string{"event_selector", "event_debounce", "event_rate"} {
+			if values[key] != "" {
+				return fmt.Errorf("label %q: only valid with on_event", dm.jobLabel(name, key))
+			}
+		}
+	}
+
+	switch triggers[0] {
+	case "schedule":
+		cronExpr := strings.TrimSpace(values["schedule"])
+		if err := schedule.Validate(cronExpr); err != nil {
+			return fmt.Errorf("label %q: invalid cron expression %q: %w",
+				dm.jobLabel(name, "schedule"), cronExpr, err)
+		}
+		if cronJob.Timezone != "" && schedule.Timezone(cronExpr) != "" {
+			return fmt.Errorf("label %q: schedule already sets CRON_TZ",
+				dm.jobLabel(name, "timezone"))
+		}
+
+		cronJob.Trigger = types.TriggerCron
+		cronJob.CronExpr = cronExpr
+		cronJob.LabelKey = dm.jobLabel(name, "schedule")
+
+	case "at":
+		at, err := parseAt(strings.TrimSpace(values["at"]), cronJob.Timezone)
+		if err != nil {
+			return fmt.Errorf("label %q: %w", dm.jobLabel(name, "at"), err)
+		}
+
+		cronJob.Trigger = types.TriggerAt
+		cronJob.At = at
+		cronJob.LabelKey = dm.jobLabel(name, "at")
+
+	case "on_start":
+		cronJob.Trigger = types.TriggerStart
+		cronJob.LabelKey = dm.jobLabel(name, "on_start")
+
+		if cronJob.StartDelay, err = dm.durationLabel(name, "start_delay", values); err != nil {
+			return err
+		}
+		if cronJob.StartOnce, err = dm.boolLabel(name, "start_once", values); err != nil {
+			return err
+		}
+
+	case "chain":
+		cronJob.Trigger = types.TriggerChain
+		cronJob.LabelKey = dm.jobLabel(name, "command")
+
+	case "on_event":
+		cronJob.Trigger = types.TriggerEvent
+		cronJob.OnEvent = strings.TrimSpace(values["on_event"])
+		cronJob.EventSelector = values["event_selector"]
+		cronJob.LabelKey = dm.jobLabel(name, "on_event")
+
+		if _, err := ParseEventPattern(cronJob.OnEvent, cronJob.EventSelector); err != nil {
+			return fmt.Errorf("label %q: %w", dm.jobLabel(name, "on_event"), err)
+		}
+		if cronJob.EventDebounce, err = dm.durationLabel(name, "event_debounce", values); err != nil {
+			return err
+		}
+		if rate := strings.TrimSpace(values["event_rate"]); rate != "" {
+			if _, _, err := ParseRateLimit(rate); err != nil {
+				return fmt.Errorf("label %q: %w", dm.jobLabel(name, "event_rate"), err)
+			}
+			cronJob.EventRate = rate
+		}
+	}
+
+	return nil
+}
+
+// Set the catch-up policy of a named job, only scheduled jobs miss runs
+func (dm *DockerMonitor) parseCatchUp(name string, values map[string]string, cronJob *types.CronJob) error {
+	cronJob.CatchUp = strings.TrimSpace(values["catchup"])
+	if cronJob.Trigger != types.TriggerCron && cronJob.Trigger != types.TriggerAt {
+		for _, key := range []string{"catchup", "catchup_window", "catchup_limit"} {
+			if values[key] != "" {
+				return fmt.Errorf("label %q: only valid with schedule or at", dm.jobLabel(name, key))
+			}
+		}
+		return nil
+	}
+
+	switch cronJob.CatchUp {
+	case "", types.CatchUpIgnore, types.CatchUpOnce, types.CatchUpAll:
+	default:
+		return fmt.Errorf("label %q: invalid policy %q, expected ignore, once or all",
+			dm.jobLabel(name, "catchup"), cronJob.CatchUp)
+	}
+
+	var err error
+	if cronJob.CatchUpWindow, err = dm.durationLabel(name, "catchup_window", values); err != nil {
+		return err
+	}
+
+	if value := strings.TrimSpace(values["catchup_limit"]); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return fmt.Errorf("label %q: invalid count %q", dm.jobLabel(name, "catchup_limit"), value)
+		}
+		cronJob.CatchUpLimit = limit
+	}
+
+	return nil
+}
+
+// Parse an absolute run time. Times without an offset need the job's time zone.
+func parseAt(value string, timezone string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+
+	if timezone == "" {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC 3339 (2006-01-02T15:04:05Z07:00)", value)
+	}
+
+	location, err := schedule.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if at, err := time.ParseInLocation(layout, value, location); err == nil {
+			return at, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: expected RFC 3339 or 2006-01-02 15:04[:05]", value)
+}
+
+// Parse an optional non-negative duration label
+func (dm *DockerMonitor) durationLabel(name string, key string, values map[string]string) (time.Duration, error) {
+	value := strings.TrimSpace(values[key])
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("label %q: invalid duration %q", dm.jobLabel(name, key), value)
+	}
+	return d, nil
+}
+
+// Parse an optional boolean label
+func (dm *DockerMonitor) boolLabel(name string, key string, values map[string]string) (bool, error) {
+	value := strings.TrimSpace(values[key])
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("label %q: invalid boolean %q", dm.jobLabel(name, key), value)
+	}
+	return b, nil
+}
+
+// Full label key of a named job setting
+func (dm *DockerMonitor) jobLabel(name string, key string) string {
+	return dm.config.LabelPrefix + jobLabelSegment + name + "." + key
+}
+
+// Legacy cronjob('...') labels have no name, derive a stable one from the key
+func legacyJobName(labelKey string) string {
+	h := fnv.New32a()
+	h.Write([]byte(labelKey))
+	return fmt.Sprintf("cronjob-%08x", h.Sum32())
+}
+
+// Parse cron expression from label key
+func (dm *DockerMonitor) parseCronExpression(labelKey string) (string, error) {
+	// Expected format: prefix.cronjob('* * * * *').task
+	start := strings.Index(labelKey, "('")
+	if start == -1 {
+		return "", fmt.Errorf("invalid cron job format: missing (")
+	}
+
+	end := strings.Index(labelKey, "')")
+	if end == -1 || end < start {
+		return "", fmt.Errorf("invalid cron job format: missing )")
+	}
+
+	cronExpr := strings.TrimSpace(labelKey[start+2 : end])
+
+	// Validate with the same parser the scheduler uses
+	if err := schedule.Validate(cronExpr); err != nil {
+		return "", fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+	}
+
+	return cronExpr, nil
+}
@@ -2,11 +2,17 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/amir-mohammad-HP/crontask/internal/metrics"
 	"github.com/amir-mohammad-HP/crontask/internal/types"
 	"github.com/amir-mohammad-HP/crontask/pkg/logger"
 	dockerTypes "github.com/docker/docker/api/types"
@@ -14,8 +20,16 @@ import (
 	dockerEvents "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	dockerClient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/amir-mohammad-HP/crontask/pkg/docker")
+
 // Event types for communication
 type ContainerEvent struct {
 	Action      string
@@ -33,11 +47,33 @@ type ContainerInfo struct {
 }
 
 type DockerMonitor struct {
-	client     *dockerClient.Client
-	logger     *logger.StdLogger
-	config     *types.DockerConfig
-	eventsChan chan ContainerEvent
-	stopChan   chan struct{}
+	client        *dockerClient.Client
+	logger        *logger.StdLogger
+	config        *types.DockerConfig
+	eventsChan    chan ContainerEvent
+	lifecycleChan chan LifecycleEvent
+	stopChan      chan struct{}
+
+	statusMu   sync.RWMutex
+	eventState SubsystemStatus // Event subscription
+	scanState  SubsystemStatus // Initial container scan
+}
+
+// SubsystemStatus reports whether part of the monitor is working and the
+// last error it hit
+type SubsystemStatus struct {
+	OK          bool
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// Container actions that change which jobs are registered
+var registrationActions = map[string]bool{
+	"create":  true,
+	"start":   true,
+	"die":     true,
+	"destroy": true,
+	"update":  true,
 }
 
 func NewMonitor(config *types.DockerConfig, logger *logger.StdLogger) (*DockerMonitor, error) {
@@ -80,11 +116,12 @@ func NewMonitor(config *types.DockerConfig, logger *logger.StdLogger) (*DockerMo
 	}
 
 	return &DockerMonitor{
-		client:     cli,
-		logger:     logger,
-		config:     config,
-		eventsChan: make(chan ContainerEvent, 100),
-		stopChan:   make(chan struct{}),
+		client:        cli,
+		logger:        logger,
+		config:        config,
+		eventsChan:    make(chan ContainerEvent, 100),
+		lifecycleChan: make(chan LifecycleEvent, 100),
+		stopChan:      make(chan struct{}),
 	}, nil
 }
 
@@ -93,7 +130,9 @@ func (dm *DockerMonitor) Start(ctx context.Context) error {
 	dm.logger.Debug("Starting Docker monitor")
 
 	// Initial scan of existing containers
-	if err := dm.scanExistingContainers(); err != nil {
+	err := dm.scanExistingContainers()
+	dm.setStatus(&dm.scanState, err)
+	if err != nil {
 		dm.logger.Error("Failed to scan existing containers %s", err.Error())
 	}
 
@@ -111,17 +150,58 @@ func (dm *DockerMonitor) Stop() {
 	}
 }
 
+// Ping checks that the Docker engine answers
+func (dm *DockerMonitor) Ping(ctx context.Context) error {
+	if _, err := dm.client.Ping(ctx); err != nil {
+		metrics.DockerError("ping")
+		return err
+	}
+	return nil
+}
+
+// EventStatus reports whether the event subscription is active
+func (dm *DockerMonitor) EventStatus() SubsystemStatus {
+	dm.statusMu.RLock()
+	defer dm.statusMu.RUnlock()
+	return dm.eventState
+}
+
+// ScanStatus reports whether the initial container scan completed
+func (dm *DockerMonitor) ScanStatus() SubsystemStatus {
+	dm.statusMu.RLock()
+	defer dm.statusMu.RUnlock()
+	return dm.scanState
+}
+
+// setStatus marks a subsystem as working, or failing with err
+func (dm *DockerMonitor) setStatus(status *SubsystemStatus, err error) {
+	dm.statusMu.Lock()
+	defer dm.statusMu.Unlock()
+
+	status.OK = err == nil
+	if err != nil {
+		status.LastError = err.Error()
+		status.LastErrorAt = time.Now()
+	}
+}
+
 // GetEvents returns a channel to receive container events
 func (dm *DockerMonitor) GetEvents() <-chan ContainerEvent {
 	return dm.eventsChan
 }
 
+// GetLifecycleEvents returns a channel to receive every raw container event
+func (dm *DockerMonitor) GetLifecycleEvents() <-chan LifecycleEvent {
+	return dm.lifecycleChan
+}
+
 // Scan all existing containers
 func (dm *DockerMonitor) scanExistingContainers() error {
 	containers, err := dm.client.ContainerList(context.Background(), container.ListOptions{
 		All: true,
 	})
 	if err != nil {
+		metrics.DockerError("container_list")
 		return err
 	}
 
@@ -144,38 +224,112 @@ func (dm *DockerMonitor) scanExistingContainers() error {
 	return nil
 }
 
-// Monitor Docker events in real-time
+// Monitor Docker events in real-time, resubscribing when the stream breaks
 func (dm *DockerMonitor) monitorEvents(ctx context.Context) {
 	filter := filters.NewArgs()
 	filter.Add("type", "container")
-	filter.Add("event", "create")
-	filter.Add("event", "start")
-	filter.Add("event", "die")
-	filter.Add("event", "destroy")
-	filter.Add("event", "update")
-
-	eventsChan, errs := dm.client.Events(ctx, dockerTypes.EventsOptions{
-		Filters: filter,
-	})
+
+	// Time of the last event seen, a new subscription replays what came after
+	var since time.Time
+
+	for {
+		options := dockerTypes.EventsOptions{Filters: filter}
+		if !since.IsZero() {
+			next := since.Add(time.Nanosecond)
+			options.Since = fmt.Sprintf("%d.%09d", next.Unix(), next.Nanosecond())
+		}
+
+		eventsChan, errs := dm.client.Events(ctx, options)
+		if !dm.consumeEvents(ctx, eventsChan, errs, &since) {
+			return
+		}
+
+		select {
+		case <-time.After(dm.config.PollInterval):
+		case <-ctx.Done():
+			return
+		case <-dm.stopChan:
+			return
+		}
+
+		metrics.EventStreamReconnects.Inc()
+		dm.logger.Info("Resubscribing to Docker events")
+	}
+}
+
+// Read one event subscription until it fails, false when monitoring stops
+func (dm *DockerMonitor) consumeEvents(ctx context.Context, eventsChan <-chan dockerEvents.Message, errs <-chan error, since *time.Time) bool {
+	// The subscription only counts as working once an event arrives or the
+	// engine answers a ping sent after subscribing, until then the last
+	// failure stands
+	confirmed := false
+	pinged := make(chan error, 1)
+	go func() { pinged <- dm.Ping(ctx) }()
 
 	for {
 		select {
+		case err := <-pinged:
+			pinged = nil
+			if err == nil && !confirmed {
+				confirmed = true
+				dm.setStatus(&dm.eventState, nil)
+			}
 		case event := <-eventsChan:
-			dm.handleEvent(event)
+			if !confirmed {
+				confirmed = true
+				dm.setStatus(&dm.eventState, nil)
+			}
+			*since = time.Unix(0, event.TimeNano)
+			dm.publishLifecycleEvent(event)
+			if registrationActions[string(event.Action)] {
+				dm.handleEvent(event)
+			}
 		case err := <-errs:
-			if err != nil {
-				dm.logger.Error("Docker events error %s", err.Error())
+			if ctx.Err() != nil {
+				return false
 			}
+			metrics.DockerError("events")
+			dm.setStatus(&dm.eventState, err)
+			dm.logger.Error("Docker events error %s", err.Error())
+			return true
 		case <-ctx.Done():
 			dm.logger.Debug("Docker monitor context done")
-			return
+			return false
 		case <-dm.stopChan:
 			dm.logger.Debug("Docker monitor stopped")
-			return
+			return false
 		}
 	}
 }
 
+// Forward a raw event to event-triggered jobs
+func (dm *DockerMonitor) publishLifecycleEvent(event dockerEvents.Message) {
+	action := string(event.Action)
+
+	// Our own execs show up as exec_create/exec_start/exec_die
+	if strings.HasPrefix(action, "exec_") {
+		return
+	}
+
+	lifecycleEvent := LifecycleEvent{
+		Type:        string(event.Type),
+		Action:      action,
+		ContainerID: event.Actor.ID,
+		Name:        event.Actor.Attributes["name"],
+		Image:       event.Actor.Attributes["image"],
+		Attributes:  event.Actor.Attributes,
+		Time:        time.Unix(0, event.TimeNano),
+	}
+
+	select {
+	case dm.lifecycleChan <- lifecycleEvent:
+	default:
+		dm.logger.Warn("Dropping container event, consumer is too slow | %s: %s, %s: %s",
+			"action", action,
+			"container", lifecycleEvent.Name)
+	}
+}
+
 // Handle individual Docker events
 func (dm *DockerMonitor) handleEvent(event dockerEvents.Message) {
 	// Give container a moment to fully start
@@ -197,10 +351,16 @@ func (dm *DockerMonitor) handleEvent(event dockerEvents.Message) {
 	}
 }
 
+// InspectContainer returns a container by name or ID
+func (dm *DockerMonitor) InspectContainer(ref string) (*ContainerInfo, error) {
+	return dm.getContainerInfo(ref)
+}
+
 // Get detailed container information
 func (dm *DockerMonitor) getContainerInfo(containerID string) (*ContainerInfo, error) {
 	containerJSON, err := dm.client.ContainerInspect(context.Background(), containerID)
 	if err != nil {
+		metrics.DockerError("container_inspect")
 		return nil, err
 	}
 
@@ -235,110 +395,174 @@ func (dm *DockerMonitor) getContainerInfo(containerID string) (*ContainerInfo, e
 	}, nil
 }
 
-// Extract cron jobs from container labels
-func (dm *DockerMonitor) ExtractCronJobs(container *ContainerInfo) []types.CronJob {
-	var cronJobs []types.CronJob
-
-	for labelKey, task := range container.Labels {
-		if strings.HasPrefix(labelKey, dm.config.LabelPrefix) {
-			cronExpr, err := dm.parseCronExpression(labelKey)
-			if err != nil {
-				dm.logger.Warn("Failed to parse cron expression | %s: %s, %s",
-					"label", labelKey,
-					err.Error())
-				continue
-			}
-
-			cronJobs = append(cronJobs, types.CronJob{
-				ContainerID:   container.ID,
-				ContainerName: container.Name,
-				CronExpr:      cronExpr,
-				Task:          task,
-				LabelKey:      labelKey,
-				IsActive:      container.State == "running",
-				CreatedAt:     time.Now(),
-			})
-		}
-	}
-
-	return cronJobs
+// ExecResult is the outcome of a task executed inside a container
+type ExecResult struct {
+	ExecID   string
+	Output   string
+	ExitCode int
 }
 
-// Parse cron expression from label key
-func (dm *DockerMonitor) parseCronExpression(labelKey string) (string, error) {
-	// Expected format: prefix.cronjob('* * * * *').task
-	start := strings.Index(labelKey, "('")
-	if start == -1 {
-		return "", fmt.Errorf("invalid cron job format: missing (")
-	}
-
-	end := strings.Index(labelKey, "')")
-	if end == -1 {
-		return "", fmt.Errorf("invalid cron job format: missing )")
-	}
+// Maximum task output kept in memory, the rest is discarded
+const maxExecOutput = 1 << 20
 
-	cronExpr := labelKey[start+2 : end]
+// Execute a task inside a container. onStart, if set, receives the exec
+// ID before the task starts.
+func (dm *DockerMonitor) ExecuteTask(ctx context.Context, containerID string, task string, options types.ExecOptions, onStart func(execID string)) (*ExecResult, error) {
+	return dm.ExecuteTaskStream(ctx, containerID, task, options, onStart, nil, nil)
+}
 
-	// Validate basic cron format (at least 5 fields)
-	parts := strings.Fields(cronExpr)
-	if len(parts) < 5 {
-		return "", fmt.Errorf("invalid cron expression: %s", cronExpr)
+// ExecuteTaskStream executes a task like ExecuteTask, copying its output to
+// stdout and stderr as it arrives when they are not nil
+func (dm *DockerMonitor) ExecuteTaskStream(ctx context.Context, containerID string, task string, options types.ExecOptions, onStart func(execID string), stdout, stderr io.Writer) (*ExecResult, error) {
+	cmd, err := Command(task, options.Shell)
+	if err != nil {
+		return nil, err
 	}
 
-	return cronExpr, nil
-}
-
-// Execute a task inside a container
-func (dm *DockerMonitor) ExecuteTask(containerID string, task string) (string, error) {
 	// Create exec instance
 	execConfig := dockerTypes.ExecConfig{
-		Cmd:          []string{"sh", "-c", task},
+		User:         options.User,
+		WorkingDir:   options.WorkDir,
+		Env:          options.Env,
+		Privileged:   options.Privileged,
+		Tty:          options.Tty,
+		Cmd:          cmd,
 		AttachStdout: true,
 		AttachStderr: true,
 	}
 
-	execID, err := dm.client.ContainerExecCreate(context.Background(), containerID, execConfig)
+	spanCtx, span := tracer.Start(ctx, "docker.exec_create")
+	execID, err := dm.client.ContainerExecCreate(spanCtx, containerID, execConfig)
+	endSpan(span, err)
 	if err != nil {
-		return "", fmt.Errorf("failed to create exec: %w", err)
+		metrics.DockerError("exec_create")
+		return nil, fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	result := &ExecResult{ExecID: execID.ID, ExitCode: -1}
+	if onStart != nil {
+		onStart(execID.ID)
 	}
 
-	// Attach to exec to get output
-	resp, err := dm.client.ContainerExecAttach(context.Background(), execID.ID, dockerTypes.ExecStartCheck{})
+	// Attach to exec to get output, the span covers the whole stream
+	spanCtx, span = tracer.Start(ctx, "docker.exec_attach",
+		trace.WithAttributes(attribute.String("crontask.exec.id", execID.ID)))
+	resp, err := dm.client.ContainerExecAttach(spanCtx, execID.ID, dockerTypes.ExecStartCheck{Tty: options.Tty})
 	if err != nil {
-		return "", fmt.Errorf("failed to attach to exec: %w", err)
+		endSpan(span, err)
+		metrics.DockerError("exec_attach")
+		return result, fmt.Errorf("failed to attach to exec: %w", err)
 	}
 	defer resp.Close()
 
-	// Read output
-	buf := make([]byte, 4096)
-	n, err := resp.Reader.Read(buf)
-	if err != nil && err.Error() != "EOF" {
+	// Read output, stdout and stderr are multiplexed on one stream unless
+	// a TTY merges them
+	output := &limitedBuffer{limit: maxExecOutput}
+	var outWriter, errWriter io.Writer = output, output
+	if stdout != nil {
+		outWriter = io.MultiWriter(output, stdout)
+	}
+	if stderr != nil {
+		errWriter = io.MultiWriter(output, stderr)
+	}
+	if options.Tty {
+		_, err = io.Copy(outWriter, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(outWriter, errWriter, resp.Reader)
+	}
+	endSpan(span, err)
+	if err != nil {
 		error := fmt.Errorf("failed to read output: %w", err)
 		dm.logger.Error("%s", error.Error())
-		return "", error
+		return result, error
 	}
 
-	output := string(buf[:n])
+	result.Output = output.String()
 
 	// Check exec status
-	inspect, err := dm.client.ContainerExecInspect(context.Background(), execID.ID)
+	spanCtx, span = tracer.Start(ctx, "docker.exec_inspect")
+	inspect, err := dm.client.ContainerExecInspect(spanCtx, execID.ID)
+	endSpan(span, err)
 	if err != nil {
-		return output, fmt.Errorf("failed to inspect exec: %w", err)
+		metrics.DockerError("exec_inspect")
+		return result, fmt.Errorf("failed to inspect exec: %w", err)
 	}
 
+	result.ExitCode = inspect.ExitCode
 	if inspect.ExitCode != 0 {
-		return output, fmt.Errorf("task exited with code %d", inspect.ExitCode)
+		return result, fmt.Errorf("task exited with code %d", inspect.ExitCode)
+	}
+
+	return result, nil
+}
+
+// endSpan marks a failed span before ending it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ErrExecNotFound is returned when Docker no longer knows an exec, for
+// example after its container was restarted or removed
+var ErrExecNotFound = errors.New("exec not found")
+
+// ExecStatus is the state of an exec as reported by Docker
+type ExecStatus struct {
+	Running  bool
+	ExitCode int
+	Pid      int // 0 when the exec was never started
+}
+
+// InspectExec returns the current state of an exec
+func (dm *DockerMonitor) InspectExec(execID string) (*ExecStatus, error) {
+	inspect, err := dm.client.ContainerExecInspect(context.Background(), execID)
+	if err != nil {
+		if dockerClient.IsErrNotFound(err) {
+			return nil, ErrExecNotFound
+		}
+		metrics.DockerError("exec_inspect")
+		return nil, fmt.Errorf("failed to inspect exec: %w", err)
 	}
 
-	return output, nil
+	return &ExecStatus{
+		Running:  inspect.Running,
+		ExitCode: inspect.ExitCode,
+		Pid:      inspect.Pid,
+	}, nil
 }
 
-// Get all running containers with cron labels
-func (dm *DockerMonitor) GetContainersWithCronJobs() ([]ContainerInfo, error) {
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (lb *limitedBuffer) Write(p []byte) (int, error) {
+	if room := lb.limit - lb.buf.Len(); room > 0 {
+		if len(p) > room {
+			lb.buf.Write(p[:room])
+		} else {
+			lb.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (lb *limitedBuffer) String() string {
+	return lb.buf.String()
+}
+
+// GetContainersWithCronJobs lists containers carrying labels with the
+// configured prefix, stopped ones included when all is set
+func (dm *DockerMonitor) GetContainersWithCronJobs(all bool) ([]ContainerInfo, error) {
 	containers, err := dm.client.ContainerList(context.Background(), container.ListOptions{
-		All: false, // Only running containers
+		All: all,
 	})
 	if err != nil {
+		metrics.DockerError("container_list")
 		return nil, err
 	}
 
@@ -360,3 +584,73 @@ func (dm *DockerMonitor) GetContainersWithCronJobs() ([]ContainerInfo, error) {
 
 	return result, nil
 }
+
+// Directories searched for commands given without a path, when the
+// container's environment has no PATH
+var binPaths = []string{"/bin", "/usr/bin", "/usr/local/bin", "/sbin", "/usr/sbin"}
+
+// HasExecutable reports whether the container's filesystem has the command,
+// an absolute path, a path relative to the working directory or a name
+// looked up in the container's PATH. It works on stopped containers too,
+// nothing is executed.
+func (dm *DockerMonitor) HasExecutable(ctx context.Context, containerID string, command string) (bool, error) {
+	var env []string
+	var workDir string
+	if !path.IsAbs(command) {
+		containerJSON, err := dm.client.ContainerInspect(ctx, containerID)
+		if err != nil {
+			metrics.DockerError("container_inspect")
+			return false, err
+		}
+		if containerJSON.Config != nil {
+			env = containerJSON.Config.Env
+			workDir = containerJSON.Config.WorkingDir
+		}
+	}
+
+	paths := executablePaths(command, env, workDir)
+	for _, p := range paths {
+		_, err := dm.client.ContainerStatPath(ctx, containerID, p)
+		if err == nil {
+			return true, nil
+		}
+		if !errdefs.IsNotFound(err) {
+			metrics.DockerError("container_stat")
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// executablePaths returns where a command is looked for, like a shell
+// would: names with a slash relative to the working directory, bare names
+// in every PATH directory
+func executablePaths(command string, env []string, workDir string) []string {
+	if path.IsAbs(command) {
+		return []string{command}
+	}
+	if strings.Contains(command, "/") {
+		if workDir == "" {
+			workDir = "/"
+		}
+		return []string{path.Join(workDir, command)}
+	}
+
+	dirs := binPaths
+	for _, variable := range env {
+		if value, ok := strings.CutPrefix(variable, "PATH="); ok {
+			dirs = strings.Split(value, ":")
+		}
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		if dir == "" {
+			dir = workDir
+		}
+		if path.IsAbs(dir) {
+			paths = append(paths, path.Join(dir, command))
+		}
+	}
+	return paths
+}
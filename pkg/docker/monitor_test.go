@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExecutablePaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		env     []string
+		workDir string
+		want    []string
+	}{
+		{
+			name:    "absolute path",
+			command: "/opt/venv/bin/python",
+			env:     []string{"PATH=/app/bin"},
+			want:    []string{"/opt/venv/bin/python"},
+		},
+		{
+			name:    "name in the container's PATH",
+			command: "app",
+			env:     []string{"LANG=C.UTF-8", "PATH=/app/bin:/usr/bin"},
+			want:    []string{"/app/bin/app", "/usr/bin/app"},
+		},
+		{
+			name:    "name without PATH",
+			command: "sh",
+			env:     []string{"LANG=C.UTF-8"},
+			want:    []string{"/bin/sh", "/usr/bin/sh", "/usr/local/bin/sh", "/sbin/sh", "/usr/sbin/sh"},
+		},
+		{
+			name:    "empty and relative PATH entries",
+			command: "app",
+			env:     []string{"PATH=:bin:/usr/bin"},
+			workDir: "/srv",
+			want:    []string{"/srv/app", "/usr/bin/app"},
+		},
+		{
+			name:    "relative path",
+			command: "./migrate",
+			env:     []string{"PATH=/usr/bin"},
+			workDir: "/app",
+			want:    []string{"/app/migrate"},
+		},
+		{
+			name:    "relative path without working directory",
+			command: "bin/migrate",
+			want:    []string{"/bin/migrate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := executablePaths(tt.command, tt.env, tt.workDir)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("executablePaths(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
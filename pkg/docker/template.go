@@ -0,0 +1,148 @@
+// pkg/docker/template.go
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template modes of a task
+const (
+	TemplateOff    = "off"    // The task is run as written
+	TemplateOn     = "on"     // Rendered before each exec, missing label keys render empty
+	TemplateStrict = "strict" // Unknown fields and labels missing from label reject the job at registration
+)
+
+// TaskData is what task templates are rendered with
+type TaskData struct {
+	JobID         string            // Job ID as listed by the API
+	JobName       string            // Job name from the labels
+	JobKey        string            // <container>/<job>
+	Trigger       string            // cron, at, start, event or chain
+	ContainerID   string            // Full container ID
+	ContainerName string            // Container name
+	Service       string            // Compose service, empty outside Compose
+	Image         string            // Image the container runs
+	Labels        map[string]string // Every label of the container
+	RunID         string            // Run ID, shared by every job of a chain
+	Scheduled     time.Time         // When the run was due
+	Attempt       int               // Attempt number, from 1
+}
+
+// RenderTask renders a task template with data. Exec form tasks have each
+// argument rendered on its own, after JSON decoding.
+func RenderTask(task string, mode string, data TaskData) (string, error) {
+	if mode == "" || mode == TemplateOff {
+		return task, nil
+	}
+
+	if !isExecForm(task) {
+		return renderTemplate(task, mode, data)
+	}
+
+	args, err := Command(task, "")
+	if err != nil {
+		return "", err
+	}
+	for i, arg := range args {
+		if args[i], err = renderTemplate(arg, mode, data); err != nil {
+			return "", err
+		}
+	}
+
+	rendered, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// checkTaskTemplate rejects a task whose template does not parse or, in
+// strict mode, does not render with the container's data
+func checkTaskTemplate(task string, mode string, data TaskData) error {
+	switch mode {
+	case "", TemplateOff:
+		return nil
+
+	case TemplateStrict:
+		_, err := RenderTask(task, mode, data)
+		return err
+	}
+
+	texts := []string{task}
+	if isExecForm(task) {
+		args, err := Command(task, "")
+		if err != nil {
+			return err
+		}
+		texts = args
+	}
+
+	for _, text := range texts {
+		if _, err := parseTemplate(text, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderTemplate(text string, mode string, data TaskData) (string, error) {
+	tmpl, err := parseTemplate(text, mode)
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(templateFuncs(mode, data.Labels))
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render task template: %w", err)
+	}
+	return rendered.String(), nil
+}
+
+func parseTemplate(text string, mode string) (*template.Template, error) {
+	missingKey := "missingkey=zero"
+	if mode == TemplateStrict {
+		missingKey = "missingkey=error"
+	}
+
+	tmpl, err := template.New("task").Option(missingKey).Funcs(templateFuncs(mode, nil)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateFuncs returns the functions available to task templates. index
+// renders missing map keys empty even in strict mode, so container labels,
+// whose keys are usually dotted, are read with label instead.
+func templateFuncs(mode string, labels map[string]string) template.FuncMap {
+	return template.FuncMap{
+		"label": func(key string) (string, error) {
+			value, ok := labels[key]
+			if !ok && mode == TemplateStrict {
+				return "", fmt.Errorf("container has no label %q", key)
+			}
+			return value, nil
+		},
+	}
+}
+
+// isExecForm reports whether a task is a JSON array of arguments
+func isExecForm(task string) bool {
+	_, ok := execArgs(task)
+	return ok
+}
+
+// execArgs decodes an exec form task, false when the task is not a JSON
+// array of strings
+func execArgs(task string) ([]string, bool) {
+	var args []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(task)), &args); err != nil || args == nil {
+		return nil, false
+	}
+	return args, true
+}
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplateLabel(t *testing.T) {
+	data := TaskData{Labels: map[string]string{"app.database": "orders"}}
+
+	tests := []struct {
+		task string
+		mode string
+		want string
+		err  bool
+	}{
+		{task: `pg_dump {{label "app.database"}}`, mode: TemplateOn, want: "pg_dump orders"},
+		{task: `pg_dump {{label "app.database"}}`, mode: TemplateStrict, want: "pg_dump orders"},
+		{task: `pg_dump {{label "app.missing"}}`, mode: TemplateOn, want: "pg_dump "},
+		{task: `pg_dump {{label "app.missing"}}`, mode: TemplateStrict, err: true},
+		{task: `["pg_dump", "{{label \"app.missing\"}}"]`, mode: TemplateStrict, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.task, func(t *testing.T) {
+			got, err := RenderTask(tt.task, tt.mode, data)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("RenderTask = %q, want an error", got)
+				}
+				if err := checkTaskTemplate(tt.task, tt.mode, data); err == nil {
+					t.Error("checkTaskTemplate = nil, want the job rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderTask = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTask = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTask(t *testing.T) {
+	data := TaskData{
+		JobName:       "backup",
+		ContainerName: "db",
+		RunID:         "0123456789abcdef",
+		Scheduled:     time.Date(2026, time.March, 8, 2, 0, 0, 0, time.UTC),
+		Attempt:       1,
+	}
+
+	tests := []struct {
+		name string
+		task string
+		mode string
+		want string
+		err  bool
+	}{
+		{name: "empty mode runs as written", task: "echo {{.JobName}}", want: "echo {{.JobName}}"},
+		{name: "off runs as written", task: "echo {{.JobName}}", mode: TemplateOff, want: "echo {{.JobName}}"},
+		{name: "on renders", task: `dump {{.ContainerName}}-{{.Scheduled.Format "2006-01-02"}}`, mode: TemplateOn, want: "dump db-2026-03-08"},
+		{name: "strict renders", task: "echo {{.RunID}} {{.Attempt}}", mode: TemplateStrict, want: "echo 0123456789abcdef 1"},
+		{name: "on rejects unknown fields", task: "echo {{.Database}}", mode: TemplateOn, err: true},
+		{name: "parse error", task: "echo {{.JobName", mode: TemplateOn, err: true},
+		{
+			name: "exec form renders each argument",
+			task: `["/app/backup", "--name", "{{.JobName}}", "{{.ContainerName}} \"quoted\""]`,
+			mode: TemplateOn,
+			want: `["/app/backup","--name","backup","db \"quoted\""]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTask(tt.task, tt.mode, data)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("RenderTask(%q) = %q, want an error", tt.task, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderTask(%q) = %v", tt.task, err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTask(%q) = %q, want %q", tt.task, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTaskExecForm(t *testing.T) {
+	data := TaskData{ContainerName: `db "main"`, Labels: map[string]string{"app.path": `C:\backups`}}
+	task := `["/app/backup", "{{.ContainerName}}", "{{label \"app.path\"}}"]`
+
+	rendered, err := RenderTask(task, TemplateOn, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rendered task is still exec form, with every value intact
+	args, err := Command(rendered, "sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/app/backup", `db "main"`, `C:\backups`}; !reflect.DeepEqual(args, want) {
+		t.Errorf("Command(rendered) = %q, want %q", args, want)
+	}
+}
+
+func TestCheckTaskTemplate(t *testing.T) {
+	data := TaskData{ContainerName: "db"}
+
+	tests := []struct {
+		name string
+		task string
+		mode string
+		err  bool
+	}{
+		{name: "off ignores templates", task: "echo {{.Database", mode: TemplateOff},
+		{name: "on accepts unknown fields until run time", task: "echo {{.Database}}", mode: TemplateOn},
+		{name: "on rejects parse errors", task: "echo {{.ContainerName", mode: TemplateOn, err: true},
+		{name: "strict rejects unknown fields", task: "echo {{.Database}}", mode: TemplateStrict, err: true},
+		{name: "strict accepts known fields", task: "echo {{.ContainerName}}", mode: TemplateStrict},
+		{name: "exec form argument parse error", task: `["echo", "{{.ContainerName"]`, mode: TemplateOn, err: true},
+		{name: "exec form strict unknown field", task: `["echo", "{{.Database}}"]`, mode: TemplateStrict, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTaskTemplate(tt.task, tt.mode, data)
+			if tt.err && err == nil {
+				t.Errorf("checkTaskTemplate(%q) = nil, want the job rejected", tt.task)
+			}
+			if !tt.err && err != nil {
+				t.Errorf("checkTaskTemplate(%q) = %v, want nil", tt.task, err)
+			}
+		})
+	}
+}
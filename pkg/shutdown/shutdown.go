@@ -9,9 +9,14 @@ import (
 
 type Task func() error
 
+type namedTask struct {
+	name string
+	task Task
+}
+
 type Manager struct {
 	logger   *logger.StdLogger
-	tasks    map[string]Task
+	tasks    []namedTask // Run in registration order
 	shutdown chan struct{}
 	timeout  time.Duration
 }
@@ -19,14 +24,15 @@ type Manager struct {
 func NewManager(logger *logger.StdLogger) *Manager {
 	return &Manager{
 		logger:   logger,
-		tasks:    make(map[string]Task),
 		shutdown: make(chan struct{}),
 		timeout:  30 * time.Second,
 	}
 }
 
+// RegisterTask adds a task to run on shutdown, after the ones registered
+// before it
 func (m *Manager) RegisterTask(name string, task Task) {
-	m.tasks[name] = task
+	m.tasks = append(m.tasks, namedTask{name: name, task: task})
 }
 
 func (m *Manager) Initiate() {
@@ -53,10 +59,10 @@ func (m *Manager) executeTasks() error {
 
 	m.logger.Debug("shutdown | executing %d tasks before shutdown", len(m.tasks))
 	var task_num int = 1
-	for name, task := range m.tasks {
-		m.logger.Info("shutdown | Executing shutdown task [%d]: %s", task_num, name)
-		if err := task(); err != nil {
-			m.logger.Error("shutdown | Task failed, task: %s, error: %s", name, err)
+	for _, t := range m.tasks {
+		m.logger.Info("shutdown | Executing shutdown task [%d]: %s", task_num, t.name)
+		if err := t.task(); err != nil {
+			m.logger.Error("shutdown | Task failed, task: %s, error: %s", t.name, err)
 		}
 		task_num++
 	}